@@ -0,0 +1,202 @@
+//nolint:varnamelen,nonamedreturns,ireturn
+package wgs84
+
+import (
+	"fmt"
+	"math"
+)
+
+// Area is the area of use of a CRS as a bounding box in degrees. West might
+// be greater than East if the area crosses the antimeridian.
+type Area struct {
+	Name                     string
+	West, South, East, North float64
+}
+
+func (a Area) Contains(lon, lat float64) bool {
+	if lat < a.South || lat > a.North {
+		return false
+	}
+
+	if a.West <= a.East {
+		return lon >= a.West && lon <= a.East
+	}
+
+	return lon >= a.West || lon <= a.East
+}
+
+// WithArea attaches an area of use to a CRS.
+func WithArea(crs CRS, area Area) CRS {
+	if b, ok := crs.(bounded); ok {
+		b.area = area
+
+		return b
+	}
+
+	return bounded{crs: crs, area: area}
+}
+
+// AreaOf returns the area of use of a CRS, if it is known.
+func AreaOf(crs CRS) (Area, bool) {
+	if b, ok := crs.(bounded); ok {
+		return b.area, true
+	}
+
+	return Area{}, false
+}
+
+// Strict returns a CRS that reports ErrOutsideDomain through SafeToBase and
+// SafeFromBase (and NaN values through ToBase and FromBase) for coordinates
+// outside of its area of use. CRSs without an area of use are returned as is.
+func Strict(crs CRS) CRS {
+	b, ok := crs.(bounded)
+	if !ok {
+		return crs
+	}
+
+	// A unit only scales the coordinates of its base, which is checked instead.
+	if u, ok := b.crs.(unit); ok {
+		u.base = Strict(WithArea(u.base, b.area))
+		b.crs = u
+
+		return b
+	}
+
+	b.own = isGeographic(b.crs)
+	b.strict = b.own || isGeographic(b.crs.Base())
+
+	return b
+}
+
+// isGeographic reports whether the coordinates of a CRS are longitude and
+// latitude.
+func isGeographic(crs CRS) bool {
+	switch c := crs.(type) {
+	case bounded:
+		return isGeographic(c.crs)
+	case compound:
+		return isGeographic(c.horizontal)
+	case geographic, molodensky, ntv2, lazyGrid, geoid, lazyGeoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// bounded is a CRS with an area of use. If strict is set, either its own
+// coordinates or those of its base are checked.
+type bounded struct {
+	crs         CRS
+	area        Area
+	strict, own bool
+}
+
+func (b bounded) Base() CRS {
+	return b.crs.Base()
+}
+
+func (b bounded) Spheroid() Spheroid {
+	return b.crs.Spheroid()
+}
+
+func (b bounded) describe(fromBase bool) string {
+	return Step{CRS: b.crs, FromBase: fromBase}.String()
+}
+
+func (b bounded) check(lon, lat float64) error {
+	if !b.area.Contains(lon, lat) {
+		return fmt.Errorf("%w: %f, %f is outside of %s", ErrOutsideDomain, lon, lat, b.area.Name)
+	}
+
+	return nil
+}
+
+func (b bounded) ToBase(x, y, z float64) (float64, float64, float64) {
+	if !b.strict {
+		return b.crs.ToBase(x, y, z)
+	}
+
+	x, y, z, _ = b.SafeToBase(x, y, z)
+
+	return x, y, z
+}
+
+func (b bounded) FromBase(x, y, z float64) (float64, float64, float64) {
+	if !b.strict {
+		return b.crs.FromBase(x, y, z)
+	}
+
+	x, y, z, _ = b.SafeFromBase(x, y, z)
+
+	return x, y, z
+}
+
+func (b bounded) SafeToBase(x, y, z float64) (float64, float64, float64, error) {
+	x, y, z, _, err := b.report(false, x, y, z)
+
+	return x, y, z, err
+}
+
+func (b bounded) SafeFromBase(x, y, z float64) (float64, float64, float64, error) {
+	x, y, z, _, err := b.report(true, x, y, z)
+
+	return x, y, z, err
+}
+
+func (b bounded) report(fromBase bool, x, y, z float64) (float64, float64, float64, Status, error) {
+	// The own coordinates are the input of ToBase and the output of FromBase.
+	checkInput := b.strict && b.own != fromBase
+
+	if checkInput {
+		if err := b.check(x, y); err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), StatusOK, err
+		}
+	}
+
+	x, y, z, status, err := Step{CRS: b.crs, FromBase: fromBase}.report(x, y, z)
+	if err != nil {
+		return x, y, z, status, err
+	}
+
+	if b.strict && !checkInput {
+		if err := b.check(x, y); err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), status, err
+		}
+	}
+
+	return x, y, z, status, nil
+}
+
+func (b bounded) ToBaseSlice(xs, ys, zs []float64) error {
+	if !b.strict {
+		return Step{CRS: b.crs}.slice(xs, ys, zs)
+	}
+
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = b.SafeToBase(xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b bounded) FromBaseSlice(xs, ys, zs []float64) error {
+	if !b.strict {
+		return Step{CRS: b.crs, FromBase: true}.slice(xs, ys, zs)
+	}
+
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = b.SafeFromBase(xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAreaContains(t *testing.T) {
+	t.Parallel()
+
+	europe := Area{"Europe", -16.1, 32.88, 40.18, 84.73}
+	aleutians := Area{"Aleutians", 172.42, 51.3, -164.84, 54.34}
+
+	tests := []struct {
+		area     Area
+		lon, lat float64
+		contains bool
+	}{
+		{europe, 10, 50, true},
+		{europe, -16.1, 32.88, true},
+		{europe, 40.18, 84.73, true},
+		{europe, -16.11, 50, false},
+		{europe, 10, 84.74, false},
+		{europe, math.NaN(), 50, false},
+		{aleutians, 172.42, 52, true},
+		{aleutians, 180, 52, true},
+		{aleutians, -180, 52, true},
+		{aleutians, -164.84, 54.34, true},
+		{aleutians, 0, 52, false},
+		{aleutians, 172.41, 52, false},
+		{aleutians, -164.83, 52, false},
+		{aleutians, 175, 51.29, false},
+	}
+
+	for _, tt := range tests {
+		if contains := tt.area.Contains(tt.lon, tt.lat); contains != tt.contains {
+			t.Errorf("%s %f, %f: contains %t", tt.area.Name, tt.lon, tt.lat, contains)
+		}
+	}
+}
+
+func TestAreaOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		crs  CRS
+		name string
+		ok   bool
+	}{
+		{EPSG(25832), "Europe between 6°E and 12°E", true},
+		{EPSG(32760), "World - S hemisphere - 174°E to 180°E", true},
+		{Strict(EPSG(4326)), "World", true},
+		{WithArea(EPSG(4326), Area{Name: "Test"}), "Test", true},
+		{EPSG(4978), "", false},
+		{Geographic(nil, NewSpheroid(6378137, 298.257223563)), "", false},
+	}
+
+	for _, tt := range tests {
+		if area, ok := AreaOf(tt.crs); ok != tt.ok || area.Name != tt.name {
+			t.Errorf("%s: %q, %t, want %q, %t", tt.crs, area.Name, ok, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		from, to CRS
+		x, y     float64
+		msg      string
+	}{
+		{"projected", EPSG(4326), Strict(EPSG(25832)), 9, 50, ""},
+		{
+			"projected outside", EPSG(4326), Strict(EPSG(25832)), 50, 50,
+			"outside domain: 50.000000, 50.000000 is outside of Europe between 6°E and 12°E",
+		},
+		{"projected inverse", Strict(EPSG(25832)), EPSG(4326), 500000, 5500000, ""},
+		{
+			"projected inverse outside", Strict(EPSG(25832)), EPSG(4326), 900000, 5500000,
+			"outside domain: 14.527924, 49.520335 is outside of Europe between 6°E and 12°E",
+		},
+		{"geographic", Strict(EPSG(4258)), EPSG(4326), 10, 50, ""},
+		{
+			"geographic outside", Strict(EPSG(4258)), EPSG(4326), -100, 40,
+			"outside domain: -100.000000, 40.000000 is outside of Europe - ETRF by country",
+		},
+		{"not strict", EPSG(4326), EPSG(25832), 50, 50, ""},
+		{"no area", EPSG(4326), Strict(Geographic(nil, NewSpheroid(6378137, 298.257223563))), 50, 50, ""},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x, y, _, err := tr.Transform(tt.x, tt.y, 0)
+
+		if tt.msg == "" {
+			if err != nil || math.IsNaN(x+y) {
+				t.Errorf("%s: %f, %f, %v", tt.name, x, y, err)
+			}
+
+			continue
+		}
+
+		if !errors.Is(err, ErrOutsideDomain) || err.Error() != tt.msg {
+			t.Errorf("%s: error %v, want %s", tt.name, err, tt.msg)
+		}
+
+		if x, y, _ := Transform(tt.from, tt.to)(tt.x, tt.y, 0); !math.IsNaN(x) || !math.IsNaN(y) {
+			t.Errorf("%s: Func %f, %f, want NaN", tt.name, x, y)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+//nolint:varnamelen,gomnd,nilnil
+package wgs84
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ClipGrid returns the part of a grid shift that covers the area. The area must
+// not cross the antimeridian.
+func ClipGrid(crs CRS, area Area) (CRS, error) {
+	data, err := gridShift(crs)
+	if err != nil {
+		return nil, err
+	}
+
+	if area.West > area.East || area.South > area.North {
+		return nil, fmt.Errorf("invalid area %v", area)
+	}
+
+	// Longitudes of the grids are positive west.
+	east, west := -area.East*3600, -area.West*3600
+	south, north := area.South*3600, area.North*3600
+
+	var clip func([]*ntv2Grid) ([]*ntv2Grid, error)
+
+	clip = func(gs []*ntv2Grid) ([]*ntv2Grid, error) {
+		var clipped []*ntv2Grid
+
+		for _, g := range gs {
+			c, err := g.clip(east, west, south, north)
+			if err != nil {
+				return nil, err
+			}
+
+			if c == nil {
+				continue
+			}
+
+			if c.children, err = clip(g.children); err != nil {
+				return nil, err
+			}
+
+			clipped = append(clipped, c)
+		}
+
+		return clipped, nil
+	}
+
+	if data.grids, err = clip(data.grids); err != nil {
+		return nil, err
+	}
+
+	if len(data.grids) == 0 {
+		return nil, fmt.Errorf("%w: grid %s does not intersect %s", ErrOutsideDomain, data.name, area.Name)
+	}
+
+	data.numFile = 0
+
+	var count func([]*ntv2Grid)
+
+	count = func(gs []*ntv2Grid) {
+		for _, g := range gs {
+			data.numFile++
+			count(g.children)
+		}
+	}
+
+	count(data.grids)
+
+	return data, nil
+}
+
+// clip returns the nodes of the grid surrounding the bounds in seconds or nil.
+func (g *ntv2Grid) clip(east, west, south, north float64) (*ntv2Grid, error) {
+	if east > g.wLong || west < g.eLong || south > g.nLat || north < g.sLat {
+		return nil, nil
+	}
+
+	cols, rows := g.cols(), g.rows()
+
+	// Keep at least two nodes in each direction for the interpolation.
+	c0, c1 := widen(node((east-g.eLong)/g.longInc, cols, math.Floor), node((west-g.eLong)/g.longInc, cols, math.Ceil), cols)
+	r0, r1 := widen(node((south-g.sLat)/g.latInc, rows, math.Floor), node((north-g.sLat)/g.latInc, rows, math.Ceil), rows)
+
+	n := ntv2Grid{
+		name:    g.name,
+		parent:  g.parent,
+		created: g.created,
+		updated: g.updated,
+		sLat:    g.sLat + float64(r0)*g.latInc,
+		nLat:    g.sLat + float64(r1)*g.latInc,
+		eLong:   g.eLong + float64(c0)*g.longInc,
+		wLong:   g.eLong + float64(c1)*g.longInc,
+		latInc:  g.latInc,
+		longInc: g.longInc,
+		unit:    g.unit,
+		header: [6]float64{
+			g.header[0] + float64(r0)*g.header[4], g.header[0] + float64(r1)*g.header[4],
+			g.header[2] + float64(c0)*g.header[5], g.header[2] + float64(c1)*g.header[5],
+			g.header[4], g.header[5],
+		},
+	}
+
+	records := make(memRecords, 0, (r1-r0+1)*(c1-c0+1))
+
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			records = append(records, g.records.at(r*cols+c))
+		}
+	}
+
+	if err := g.records.err(); err != nil {
+		return nil, fmt.Errorf("subgrid %s: %w", g.name, err)
+	}
+
+	n.records = records
+	n.gsCount = int32(len(records))
+
+	return &n, nil
+}
+
+// node rounds the fractional index f of one of n nodes and clamps it.
+func node(f float64, n int, round func(float64) float64) int {
+	// Ignore rounding errors of bounds on the nodes.
+	if r := math.Round(f); math.Abs(f-r) < 1e-9 {
+		f = r
+	}
+
+	return min(max(int(round(f)), 0), n-1)
+}
+
+func widen(i0, i1, n int) (int, int) {
+	if i0 == i1 {
+		if i1 < n-1 {
+			i1++
+		} else if i0 > 0 {
+			i0--
+		}
+	}
+
+	return i0, i1
+}
+
+// MergeGrids returns a grid shift with the subgrids of all grid shifts, which
+// must share spheroid, base CRS and systems and have unique subgrid names.
+func MergeGrids(crss ...CRS) (CRS, error) {
+	if len(crss) == 0 {
+		return nil, errors.New("no grids to merge")
+	}
+
+	data, err := gridShift(crss[0])
+	if err != nil {
+		return nil, err
+	}
+
+	data.grids = nil
+	data.numFile = 0
+	data.format = ""
+
+	names := map[string]bool{}
+
+	var add func([]*ntv2Grid) error
+
+	add = func(gs []*ntv2Grid) error {
+		for _, g := range gs {
+			if names[g.name] {
+				return fmt.Errorf("invalid merge: duplicate subgrid %q", g.name)
+			}
+
+			names[g.name] = true
+			data.numFile++
+
+			if err := add(g.children); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, crs := range crss {
+		other, err := gridShift(crs)
+		if err != nil {
+			return nil, err
+		}
+
+		if other.spheroid != data.spheroid {
+			return nil, fmt.Errorf("invalid merge: spheroid %s of grid %s differs from %s", other.spheroid, other.name, data.spheroid)
+		}
+
+		if other.systemF != data.systemF || other.systemT != data.systemT {
+			return nil, fmt.Errorf("invalid merge: grid %s from %s to %s differs from %s to %s", other.name, other.systemF, other.systemT, data.systemF, data.systemT)
+		}
+
+		if !equalCRS(other.base, data.base) {
+			return nil, fmt.Errorf("invalid merge: base %v of grid %s differs from %v", other.base, other.name, data.base)
+		}
+
+		if err := add(other.grids); err != nil {
+			return nil, err
+		}
+
+		data.grids = append(data.grids, other.grids...)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,74 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMergeGrids(t *testing.T) {
+	t.Parallel()
+
+	clarke := NewSpheroid(6378206.4, 294.9786982)
+
+	grid := func(name, systemF string, spheroid Spheroid, base CRS) CRS {
+		crs, err := NewNTv2(NTv2Header{SystemF: systemF, SystemT: "NAD83"}, []NTv2Subgrid{{
+			Name: name, SLat: 162000, NLat: 162900, ELong: 270000, WLong: 270900, LatInc: 900, LongInc: 900,
+			Records: make([][4]float32, 4),
+		}}, spheroid, base)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return crs
+	}
+
+	tests := []struct {
+		name  string
+		other CRS
+		valid bool
+	}{
+		{"same", grid("B", "NAD27", clarke, nil), true},
+		{"epsg base", grid("B", "NAD27", clarke, EPSG(4326)), true},
+		{"duplicate", grid("A", "NAD27", clarke, nil), false},
+		{"spheroid", grid("B", "NAD27", NewSpheroid(6378137, 298.257223563), nil), false},
+		{"system", grid("B", "OLDHI", clarke, nil), false},
+		{"base", grid("B", "NAD27", clarke, EPSG(4269)), false},
+	}
+
+	for _, tt := range tests {
+		if _, err := MergeGrids(grid("A", "NAD27", clarke, nil), tt.other); (err == nil) != tt.valid {
+			t.Errorf("%s: error %v", tt.name, err)
+		}
+	}
+}
+
+func TestClipGridReadError(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := WriteNTv2(&buf, testGrid(t, func(_, _ float64) [4]float32 { return [4]float32{0.3, -1.5, 0.1, 0.1} })); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		limit int64
+		err   error
+	}{
+		{int64(buf.Len()), nil},
+		{22 * 16, errRead},
+	}
+
+	for _, tt := range tests {
+		crs, err := NTv2ReaderAt(failingReaderAt{r: bytes.NewReader(buf.Bytes()), limit: tt.limit}, NewSpheroid(6378206.4, 294.9786982), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ClipGrid(crs, Area{West: -75.3, South: 45.1, East: -75.1, North: 45.3}); !errors.Is(err, tt.err) {
+			t.Errorf("limit %d: error %v, want %v", tt.limit, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Command gsb prints, clips and merges grid shift files and writes them as
+// NTv2 (.gsb) files.
+//
+//	gsb info input
+//	gsb clip -bbox west,south,east,north -o output.gsb input
+//	gsb merge -o output.gsb input...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wroge/wgs84/v2"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "gsb:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: gsb info|clip|merge [flags] input...")
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	output := flags.String("o", "", "output .gsb file")
+	bbox := flags.String("bbox", "", "west,south,east,north in degrees")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	grids := make([]wgs84.CRS, flags.NArg())
+
+	for i, path := range flags.Args() {
+		grid, err := read(path)
+		if err != nil {
+			return err
+		}
+
+		grids[i] = grid
+	}
+
+	switch args[0] {
+	case "info":
+		for _, grid := range grids {
+			fmt.Println(grid)
+		}
+
+		return nil
+	case "clip":
+		if len(grids) != 1 || *output == "" {
+			return fmt.Errorf("usage: gsb clip -bbox west,south,east,north -o output.gsb input")
+		}
+
+		area, err := parseBBox(*bbox)
+		if err != nil {
+			return err
+		}
+
+		clipped, err := wgs84.ClipGrid(grids[0], area)
+		if err != nil {
+			return err
+		}
+
+		return wgs84.WriteNTv2File(*output, clipped)
+	case "merge":
+		if len(grids) == 0 || *output == "" {
+			return fmt.Errorf("usage: gsb merge -o output.gsb input...")
+		}
+
+		merged, err := wgs84.MergeGrids(grids...)
+		if err != nil {
+			return err
+		}
+
+		return wgs84.WriteNTv2File(*output, merged)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// read reads a grid. The spheroid and base are not stored in the files and
+// do not matter for the output.
+func read(path string) (wgs84.CRS, error) {
+	spheroid := wgs84.NewSpheroid(6378137, 298.257222101)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gsb":
+		return wgs84.NTv2File(path, spheroid, nil)
+	case ".las", ".los":
+		return wgs84.NADCONFile(path, spheroid, nil)
+	case ".ct2":
+		return wgs84.CTable2File(path, spheroid, nil)
+	case ".dac":
+		return wgs84.NTv1File(path, spheroid, nil)
+	case ".tif", ".tiff":
+		return wgs84.GeoTIFFFile(path, spheroid, nil)
+	default:
+		return nil, fmt.Errorf("unknown grid format of %s", path)
+	}
+}
+
+func parseBBox(s string) (wgs84.Area, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return wgs84.Area{}, fmt.Errorf("invalid bbox %q", s)
+	}
+
+	var values [4]float64
+
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return wgs84.Area{}, fmt.Errorf("invalid bbox %q: %w", s, err)
+		}
+
+		values[i] = v
+	}
+
+	return wgs84.Area{Name: s, West: values[0], South: values[1], East: values[2], North: values[3]}, nil
+}
@@ -0,0 +1,100 @@
+//nolint:varnamelen,ireturn
+package wgs84
+
+import (
+	"fmt"
+	"math"
+)
+
+// Compound returns a CRS of the horizontal coordinates of one CRS and the
+// heights of a vertical CRS, e.g. a geoid, whose base CRS it shares.
+func Compound(horizontal, vertical CRS) CRS {
+	if horizontal == nil || vertical == nil || vertical.Base() == nil {
+		return errorCRS{err: fmt.Errorf("invalid compound crs of %v and %v", horizontal, vertical)}
+	}
+
+	toBase, fromBase := NewPipeline(horizontal, vertical.Base()), NewPipeline(vertical.Base(), horizontal)
+
+	return compound{
+		horizontal: horizontal,
+		vertical:   vertical,
+		steps: &compoundSteps{
+			toBase:       toBase,
+			fromBase:     fromBase,
+			toBaseFunc:   toBase.Func(),
+			fromBaseFunc: fromBase.Func(),
+		},
+	}
+}
+
+type compound struct {
+	horizontal, vertical CRS
+	steps                *compoundSteps
+}
+
+// compoundSteps transform the horizontal coordinates. It is a pointer to keep
+// compound comparable.
+type compoundSteps struct {
+	toBase, fromBase         Pipeline
+	toBaseFunc, fromBaseFunc Func
+}
+
+func (c compound) validate() error {
+	if err := chainError(c.horizontal); err != nil {
+		return err
+	}
+
+	return chainError(c.vertical)
+}
+
+func (c compound) describe(fromBase bool) string {
+	if fromBase {
+		return fmt.Sprintf("Compound(%s, %s)", Step{CRS: c.vertical, FromBase: true}, c.steps.fromBase)
+	}
+
+	return fmt.Sprintf("Compound(%s, %s)", c.steps.toBase, Step{CRS: c.vertical})
+}
+
+func (c compound) Base() CRS {
+	return c.vertical.Base()
+}
+
+func (c compound) Spheroid() Spheroid {
+	return c.vertical.Base().Spheroid()
+}
+
+func (c compound) ToBase(x, y, h float64) (float64, float64, float64) {
+	lon, lat, _ := c.steps.toBaseFunc(x, y, h)
+
+	return c.vertical.ToBase(lon, lat, h)
+}
+
+func (c compound) FromBase(lon, lat, h float64) (float64, float64, float64) {
+	_, _, height := c.vertical.FromBase(lon, lat, h)
+	x, y, _ := c.steps.fromBaseFunc(lon, lat, h)
+
+	return x, y, height
+}
+
+func (c compound) SafeToBase(x, y, h float64) (float64, float64, float64, error) {
+	lon, lat, _, err := Transformer{pipeline: c.steps.toBase}.Transform(x, y, h)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+
+	return Step{CRS: c.vertical}.call(lon, lat, h)
+}
+
+func (c compound) SafeFromBase(lon, lat, h float64) (float64, float64, float64, error) {
+	_, _, height, err := Step{CRS: c.vertical, FromBase: true}.call(lon, lat, h)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+
+	x, y, _, err := Transformer{pipeline: c.steps.fromBase}.Transform(lon, lat, h)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+
+	return x, y, height, nil
+}
@@ -0,0 +1,19 @@
+//go:build !wgs84_noembed
+
+package wgs84
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed ntv2
+var res embed.FS
+
+// embedded returns the grids embedded in this package. Build with the tag
+// wgs84_noembed to exclude them from the binary.
+func embedded() fs.FS {
+	sub, _ := fs.Sub(res, "ntv2")
+
+	return sub
+}
@@ -8,6 +8,8 @@ import (
 
 var crsStore sync.Map
 
+// EPSG returns the CRS of an EPSG code or an errorCRS with ErrUnknownCode.
+// NAD27 State Plane zones 26731, 26761 to 26765 and 26788 to 26790 are missing.
 func EPSG(code int) CRS {
 	if crs, ok := crsStore.Load(code); ok {
 		return crs.(CRS)
@@ -54,34 +56,45 @@ func EPSG(code int) CRS {
 		crs = LambertConformalConic2SP(EPSG(4269), -85, 0, 44.5, 53.5, 930000, 6430000)
 	case 3416:
 		crs = LambertConformalConic2SP(EPSG(4258), 13.33333333333333, 47.5, 49, 46, 400000, 400000)
+	case 3855:
+		crs = DefaultGridResolver.Geoid("egm08_25.gtx", EPSG(4326))
 	case 3857:
 		crs = WebMercator(EPSG(4326))
 	case 4156:
-		crs = Geographic(Helmert(589, 76, 480, 0, 0, 0, 0), NewSpheroid(6377397.155, 299.1528128))
+		// EPSG 1622, geocentric translations (9603).
+		crs = Geographic(HelmertPV(589, 76, 480, 0, 0, 0, 0), NewSpheroid(6377397.155, 299.1528128))
 	case 4171:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257222101))
 	case 4173:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257222101))
 	case 4188:
-		crs = Geographic(Helmert(482.5, -130.6, 564.6, -1.042, -0.214, -0.631, 8.15), NewSpheroid(6377563.396, 299.3249646))
+		// EPSG 1955, Position Vector (9606).
+		crs = Geographic(HelmertPV(482.5, -130.6, 564.6, -1.042, -0.214, -0.631, 8.15), NewSpheroid(6377563.396, 299.3249646))
 	case 4230:
-		crs = Geographic(Helmert(-87, -98, -121, 0, 0, 0, 0), NewSpheroid(6378388, 297))
+		// EPSG 1133, geocentric translations (9603).
+		crs = Geographic(HelmertPV(-87, -98, -121, 0, 0, 0, 0), NewSpheroid(6378388, 297))
 	case 4258:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257222101))
-	// case 4267:
-	// 	crs = loadNTv2("NTv2_0.gsb", NewSpheroid(6378206.4, 294.978698213898), EPSG(4326))
+	case 4267:
+		// NADCON, otherwise EPSG 1173, geocentric translations (9603).
+		crs = WithOutOfGrid(DefaultGridResolver.NADCON("conus", NewSpheroid(6378206.4, 294.978698213898), EPSG(4269)),
+			OutOfGridFallback, Geographic(HelmertPV(-8, 160, 176, 0, 0, 0, 0), NewSpheroid(6378206.4, 294.978698213898)))
 	case 4269:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257222101))
 	case 4277:
-		crs = loadNTv2("OSTN15_NTv2_OSGBtoETRS.gsb", NewSpheroid(6377563.396, 299.3249646), EPSG(4326))
+		// OSTN15, otherwise EPSG 1314, Position Vector (9606).
+		crs = WithOutOfGrid(DefaultGridResolver.NTv2("OSTN15_NTv2_OSGBtoETRS.gsb", NewSpheroid(6377563.396, 299.3249646), EPSG(4326)),
+			OutOfGridFallback, Geographic(HelmertPV(446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489), NewSpheroid(6377563.396, 299.3249646)))
 	case 4299:
-		crs = Geographic(Helmert(482.5, -130.6, 564.6, -1.042, -0.214, -0.631, 8.15), NewSpheroid(6377340.189, 299.3249646))
+		// EPSG 1641, Position Vector (9606).
+		crs = Geographic(HelmertPV(482.5, -130.6, 564.6, -1.042, -0.214, -0.631, 8.15), NewSpheroid(6377340.189, 299.3249646))
 	case 4300:
 		crs = EPSG(4299)
 	case 4312:
-		crs = Geographic(Helmert(577.326, 90.129, 463.919, 5.137, 1.474, 5.297, 2.4232), NewSpheroid(6377397.155, 299.1528128))
+		// EPSG 1618, Position Vector (9606).
+		crs = Geographic(HelmertPV(577.326, 90.129, 463.919, 5.137, 1.474, 5.297, 2.4232), NewSpheroid(6377397.155, 299.1528128))
 	case 4314:
-		crs = loadNTv2("BeTA2007.gsb", NewSpheroid(6377397.155, 299.1528128), EPSG(4326))
+		crs = DefaultGridResolver.NTv2("BeTA2007.gsb", NewSpheroid(6377397.155, 299.1528128), EPSG(4326))
 	case 4326:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257223563))
 	case 4490:
@@ -92,6 +105,16 @@ func EPSG(code int) CRS {
 		crs = base{}
 	case 5514:
 		crs = Krovak(EPSG(4156), 24.8333333333333, 49.5, 30.2881397527778, 78.5, 0.9999, 0, 0)
+	case 5555:
+		crs = Compound(EPSG(25832), EPSG(5783))
+	case 5556:
+		crs = Compound(EPSG(25833), EPSG(5783))
+	case 5701:
+		crs = DefaultGridResolver.Geoid("OSGM15_GB.gtx", EPSG(4258))
+	case 5773:
+		crs = DefaultGridResolver.Geoid("egm96_15.gtx", EPSG(4326))
+	case 5783:
+		crs = DefaultGridResolver.Geoid("GCG2011.isg", EPSG(4258))
 	case 6318:
 		crs = Geographic(EPSG(4978), NewSpheroid(6378137, 298.257222101))
 	case 6355:
@@ -100,8 +123,140 @@ func EPSG(code int) CRS {
 		crs = TransverseMercator(EPSG(6318), -87.5, 30, 0.999933333, 600000, 0)
 	case 6414:
 		crs = AlbersConicEqualArea(EPSG(6318), -120, 0, 34, 40.5, 0, -4000000)
+	case 7405:
+		crs = Compound(EPSG(27700), EPSG(5701))
+	case 7837:
+		crs = DefaultGridResolver.Geoid("GCG2016.isg", EPSG(4258))
+	case 9518:
+		crs = Compound(EPSG(4326), EPSG(3855))
+	case 9707:
+		crs = Compound(EPSG(4326), EPSG(5773))
 	case 23090:
 		crs = TransverseMercator(EPSG(4230), 0, 0, 0.9996, 500000, 0)
+	case 26729:
+		crs = spcs27TM(-85.8333333333333, 30.5, 0.99996)
+	case 26730:
+		crs = spcs27TM(-87.5, 30, 0.999933333)
+	case 26732:
+		crs = spcs27TM(-142, 54, 0.9999)
+	case 26733:
+		crs = spcs27TM(-146, 54, 0.9999)
+	case 26734:
+		crs = spcs27TM(-150, 54, 0.9999)
+	case 26735:
+		crs = spcs27TM(-154, 54, 0.9999)
+	case 26736:
+		crs = spcs27TM(-158, 54, 0.9999)
+	case 26737:
+		crs = spcs27TM(-162, 54, 0.9999)
+	case 26738:
+		crs = spcs27TM(-166, 54, 0.9999)
+	case 26739:
+		crs = spcs27TM(-170, 54, 0.9999)
+	case 26740:
+		crs = spcs27LCC(-176, 51, 51.8333333333333, 53.8333333333333, 3000000)
+	case 26741:
+		crs = spcs27LCC(-122, 39.3333333333333, 40, 41.6666666666667, 2000000)
+	case 26742:
+		crs = spcs27LCC(-122, 37.6666666666667, 38.3333333333333, 39.8333333333333, 2000000)
+	case 26743:
+		crs = spcs27LCC(-120.5, 36.5, 37.0666666666667, 38.4333333333333, 2000000)
+	case 26744:
+		crs = spcs27LCC(-119, 35.3333333333333, 36, 37.25, 2000000)
+	case 26745:
+		crs = spcs27LCC(-118, 33.5, 34.0333333333333, 35.4666666666667, 2000000)
+	case 26746:
+		crs = spcs27LCC(-116.25, 32.1666666666667, 32.7833333333333, 33.8833333333333, 2000000)
+	case 26747:
+		crs = Unit(LambertConformalConic2SP(EPSG(4267), -118.333333333333, 34.1333333333333, 33.8666666666667, 34.4166666666667, 4186692.58*USSurveyFoot, 416926.74*USSurveyFoot), USSurveyFoot)
+	case 26748:
+		crs = spcs27TM(-110.166666666667, 31, 0.9999)
+	case 26749:
+		crs = spcs27TM(-111.916666666667, 31, 0.9999)
+	case 26750:
+		crs = spcs27TM(-113.75, 31, 0.999933333)
+	case 26751:
+		crs = spcs27LCC(-92, 34.3333333333333, 34.9333333333333, 36.2333333333333, 2000000)
+	case 26752:
+		crs = spcs27LCC(-92, 32.6666666666667, 33.3, 34.7666666666667, 2000000)
+	case 26753:
+		crs = spcs27LCC(-105.5, 39.3333333333333, 39.7166666666667, 40.7833333333333, 2000000)
+	case 26754:
+		crs = spcs27LCC(-105.5, 37.8333333333333, 38.45, 39.75, 2000000)
+	case 26755:
+		crs = spcs27LCC(-105.5, 36.6666666666667, 37.2333333333333, 38.4333333333333, 2000000)
+	case 26756:
+		crs = spcs27LCC(-72.75, 40.8333333333333, 41.2, 41.8666666666667, 600000)
+	case 26757:
+		crs = spcs27TM(-75.4166666666667, 38, 0.999995)
+	case 26758:
+		crs = spcs27TM(-81, 24.3333333333333, 0.999941177)
+	case 26759:
+		crs = spcs27TM(-82, 24.3333333333333, 0.999941177)
+	case 26760:
+		crs = spcs27LCC(-84.5, 29, 29.5833333333333, 30.75, 2000000)
+	case 26766:
+		crs = spcs27TM(-82.1666666666667, 30, 0.9999)
+	case 26767:
+		crs = spcs27TM(-84.1666666666667, 30, 0.9999)
+	case 26768:
+		crs = spcs27TM(-112.166666666667, 41.6666666666667, 0.999947368)
+	case 26769:
+		crs = spcs27TM(-114, 41.6666666666667, 0.999947368)
+	case 26770:
+		crs = spcs27TM(-115.75, 41.6666666666667, 0.999933333)
+	case 26771:
+		crs = spcs27TM(-88.3333333333333, 36.6666666666667, 0.999975)
+	case 26772:
+		crs = spcs27TM(-90.1666666666667, 36.6666666666667, 0.999941177)
+	case 26773:
+		crs = spcs27TM(-85.6666666666667, 37.5, 0.999966667)
+	case 26774:
+		crs = spcs27TM(-87.0833333333333, 37.5, 0.999966667)
+	case 26775:
+		crs = spcs27LCC(-93.5, 41.5, 42.0666666666667, 43.2666666666667, 2000000)
+	case 26776:
+		crs = spcs27LCC(-93.5, 40, 40.6166666666667, 41.7833333333333, 2000000)
+	case 26777:
+		crs = spcs27LCC(-98, 38.3333333333333, 38.7166666666667, 39.7833333333333, 2000000)
+	case 26778:
+		crs = spcs27LCC(-98.5, 36.6666666666667, 37.2666666666667, 38.5666666666667, 2000000)
+	case 26779:
+		crs = spcs27LCC(-84.25, 37.5, 37.9666666666667, 38.9666666666667, 2000000)
+	case 26780:
+		crs = spcs27LCC(-85.75, 36.3333333333333, 36.7333333333333, 37.9333333333333, 2000000)
+	case 26781:
+		crs = spcs27LCC(-92.5, 30.6666666666667, 31.1666666666667, 32.6666666666667, 2000000)
+	case 26782:
+		crs = spcs27LCC(-91.3333333333333, 28.6666666666667, 29.3, 30.7, 2000000)
+	case 26783:
+		crs = spcs27TM(-68.5, 43.8333333333333, 0.9999)
+	case 26784:
+		crs = spcs27TM(-70.1666666666667, 42.8333333333333, 0.999966667)
+	case 26785:
+		crs = spcs27LCC(-77, 37.8333333333333, 38.3, 39.45, 800000)
+	case 26786:
+		crs = spcs27LCC(-71.5, 41, 41.7166666666667, 42.6833333333333, 600000)
+	case 26787:
+		crs = spcs27LCC(-70.5, 41, 41.2833333333333, 41.4833333333333, 200000)
+	case 26791:
+		crs = spcs27LCC(-93.1, 46.5, 47.0333333333333, 48.6333333333333, 2000000)
+	case 26792:
+		crs = spcs27LCC(-94.25, 45, 45.6166666666667, 47.05, 2000000)
+	case 26793:
+		crs = spcs27LCC(-94, 43, 43.7833333333333, 45.2166666666667, 2000000)
+	case 26794:
+		crs = spcs27TM(-88.8333333333333, 29.6666666666667, 0.99996)
+	case 26795:
+		crs = spcs27TM(-90.3333333333333, 30.5, 0.999941177)
+	case 26796:
+		crs = spcs27TM(-90.5, 35.8333333333333, 0.999933333)
+	case 26797:
+		crs = spcs27TM(-92.5, 35.8333333333333, 0.999933333)
+	case 26798:
+		crs = spcs27TM(-94.5, 36.1666666666667, 0.999941177)
+	case 26799:
+		crs = Unit(LambertConformalConic2SP(EPSG(4267), -118.333333333333, 34.1333333333333, 33.8666666666667, 34.4166666666667, 4186692.58*USSurveyFoot, 4160926.74*USSurveyFoot), USSurveyFoot)
 	case 26917:
 		crs = TransverseMercator(EPSG(4269), -81, 0, 0.9996, 500000, 0)
 	case 27700:
@@ -126,8 +281,8 @@ func EPSG(code int) CRS {
 		crs = TransverseMercator(EPSG(4312), 16.33333333333333, 0, 1, 750000, 0)
 	case 31287:
 		crs = LambertConformalConic2SP(EPSG(4312), 13.33333333333333, 47.5, 49, 46, 400000, 400000)
-	// case 32024:
-	// 	crs = LambertConformalConic2SP(EPSG(4267), -98, 35, 35.5666666666667, 36.7666666666667, 2000000, 0)
+	case 32024:
+		crs = spcs27LCC(-98, 35, 35.5666666666667, 36.7666666666667, 2000000)
 	case 102109:
 		crs = TransverseMercator(EPSG(4258), 15, 0, 0.9999, 500000, -5000000)
 	case 102157:
@@ -142,6 +297,10 @@ func EPSG(code int) CRS {
 			lat := float64(code - 3900)
 
 			crs = LambertConformalConic2SP(EPSG(4171), 3, lat, lat-0.75, lat+0.75, 1700000, 2200000+(lat-43)*1000000)
+		case code > 26700 && code < 26723:
+			zone := float64(code - 26700)
+
+			crs = TransverseMercator(EPSG(4267), zone*6-183, 0, 0.9996, 500000, 0)
 		case code > 25827 && code < 25839:
 			zone := float64(code - 25800)
 
@@ -162,10 +321,266 @@ func EPSG(code int) CRS {
 	}
 
 	if crs == nil {
-		return errorCRS{err: fmt.Errorf("epsg code '%d' not found", code)}
+		return errorCRS{err: fmt.Errorf("%w: epsg code '%d' not found", ErrUnknownCode, code)}
+	}
+
+	if area, ok := epsgArea(code); ok {
+		crs = WithArea(crs, area)
 	}
 
 	crsStore.Store(code, crs)
 
 	return crs
 }
+
+// epsgArea returns the (rounded) area of use of an EPSG code.
+func epsgArea(code int) (Area, bool) {
+	switch code {
+	case 2154, 4171:
+		return Area{"France - onshore and offshore", -9.86, 41.15, 10.38, 51.56}, true
+	case 2157, 2158, 4173, 4299, 4300, 29902, 29903:
+		return Area{"Ireland - onshore. UK - Northern Ireland (Ulster) - onshore", -10.56, 51.39, -5.34, 55.43}, true
+	case 3035:
+		return Area{"Europe - European Union (EU) countries and candidates", -35.58, 24.6, 44.83, 84.73}, true
+	case 3161:
+		return Area{"Canada - Ontario", -95.16, 41.67, -74.35, 56.9}, true
+	case 3416, 4312, 31287:
+		return Area{"Austria", 9.53, 46.4, 17.17, 49.02}, true
+	case 3857, 900913:
+		return Area{"World between 85.06°S and 85.06°N", -180, -85.06, 180, 85.06}, true
+	case 4156, 5514:
+		return Area{"Czechia; Slovakia", 12.09, 47.73, 22.56, 51.06}, true
+	case 4188, 29901:
+		return Area{"UK - Northern Ireland (Ulster) - onshore", -8.18, 53.96, -5.34, 55.36}, true
+	case 4230:
+		return Area{"Europe - ED50 by country", -16.1, 25.71, 48.61, 84.73}, true
+	case 4258:
+		return Area{"Europe - ETRF by country", -16.1, 32.88, 40.18, 84.73}, true
+	case 4267:
+		return Area{"North America - NAD27", -172.54, 7.15, -47.74, 86.46}, true
+	case 4269:
+		return Area{"North America - NAD83", -172.54, 14.92, -47.74, 86.46}, true
+	case 4277:
+		return Area{"UK - Great Britain; Isle of Man", -8.82, 49.79, 1.92, 60.94}, true
+	case 4314, 5783, 7837:
+		return Area{"Germany", 5.87, 47.27, 15.04, 55.09}, true
+	case 3855, 4326, 5773, 9518, 9707:
+		return Area{"World", -180, -90, 180, 90}, true
+	case 4490:
+		return Area{"China", 73.62, 16.7, 134.77, 53.56}, true
+	case 4549:
+		return Area{"China - 118.5°E to 121.5°E", 118.5, 24.43, 121.5, 53.33}, true
+	case 5555:
+		return Area{"Germany - onshore west of 12°E", 5.87, 47.27, 12, 55.09}, true
+	case 5556:
+		return Area{"Germany - onshore east of 12°E", 12, 47.46, 15.04, 54.74}, true
+	case 5701, 7405:
+		return Area{"UK - Great Britain mainland onshore", -7.06, 49.93, 1.8, 58.71}, true
+	case 6318:
+		return Area{"USA - NAD83(2011)", -180, 14.92, -63.88, 74.71}, true
+	case 6355, 26729:
+		return Area{"USA - Alabama - SPCS - E", -86.79, 30.99, -84.89, 35}, true
+	case 6356, 26730:
+		return Area{"USA - Alabama - SPCS - W", -88.48, 30.14, -86.3, 35.02}, true
+	case 6414:
+		return Area{"USA - California", -124.45, 32.53, -114.12, 42.01}, true
+	case 23090:
+		return Area{"Europe - 6°W to 6°E", -6, 35.26, 6, 62.33}, true
+	case 26732:
+		return Area{"USA - Alaska - 144°W to 141°W", -144.01, 59.72, -140.98, 70.16}, true
+	case 26733:
+		return Area{"USA - Alaska - 148°W to 144°W", -148, 59.72, -144, 70.38}, true
+	case 26734:
+		return Area{"USA - Alaska - 152°W to 148°W", -152.01, 59.11, -147.99, 70.63}, true
+	case 26735:
+		return Area{"USA - Alaska - 156°W to 152°W", -156, 55.72, -151.86, 71.16}, true
+	case 26736:
+		return Area{"USA - Alaska - 160°W to 156°W", -160, 54.89, -155.99, 71.4}, true
+	case 26737:
+		return Area{"USA - Alaska - 164°W to 160°W", -164.01, 54.32, -160, 70.74}, true
+	case 26738:
+		return Area{"USA - Alaska - north of 54.5°N; 168°W to 164°W", -168.26, 54.34, -164, 69.05}, true
+	case 26739:
+		return Area{"USA - Alaska - north of 54.5°N; west of 168°W", -173.16, 56.49, -168.58, 65.82}, true
+	case 26740:
+		return Area{"USA - Alaska - Aleutian Islands", 172.42, 51.3, -164.84, 54.34}, true
+	case 26741:
+		return Area{"USA - California - SPCS - 1", -124.45, 39.59, -119.99, 42.01}, true
+	case 26742:
+		return Area{"USA - California - SPCS - 2", -124.06, 38.02, -119.54, 40.16}, true
+	case 26743:
+		return Area{"USA - California - SPCS - 3", -123.02, 36.73, -117.83, 38.71}, true
+	case 26744:
+		return Area{"USA - California - SPCS - 4", -122.01, 35.78, -115.62, 37.58}, true
+	case 26745:
+		return Area{"USA - California - SPCS - 5", -121.42, 32.76, -114.12, 35.81}, true
+	case 26746:
+		return Area{"USA - California - SPCS - 6", -118.15, 32.53, -114.42, 34.08}, true
+	case 26747, 26799:
+		return Area{"USA - California - SPCS27 - 7", -118.96, 33.66, -117.63, 34.83}, true
+	case 26748:
+		return Area{"USA - Arizona - SPCS - E", -111.71, 31.33, -109.04, 37.01}, true
+	case 26749:
+		return Area{"USA - Arizona - SPCS - C", -113.35, 31.33, -110.44, 37.01}, true
+	case 26750:
+		return Area{"USA - Arizona - SPCS - W", -114.82, 32.05, -112.52, 37}, true
+	case 26751:
+		return Area{"USA - Arkansas - SPCS - N", -94.62, 34.67, -89.64, 36.5}, true
+	case 26752:
+		return Area{"USA - Arkansas - SPCS - S", -94.48, 33.01, -90.4, 35.1}, true
+	case 26753:
+		return Area{"USA - Colorado - SPCS - N", -109.06, 39.56, -102.04, 41.01}, true
+	case 26754:
+		return Area{"USA - Colorado - SPCS - C", -109.06, 38.14, -102.04, 40.09}, true
+	case 26755:
+		return Area{"USA - Colorado - SPCS - S", -109.06, 36.98, -102.04, 38.68}, true
+	case 26756:
+		return Area{"USA - Connecticut", -73.73, 40.98, -71.78, 42.05}, true
+	case 26757:
+		return Area{"USA - Delaware", -75.8, 38.44, -74.97, 39.85}, true
+	case 26758:
+		return Area{"USA - Florida - SPCS - E", -82.33, 24.41, -79.97, 30.83}, true
+	case 26759:
+		return Area{"USA - Florida - SPCS - W", -83.34, 26.27, -81.13, 29.6}, true
+	case 26760:
+		return Area{"USA - Florida - SPCS - N", -87.63, 29.21, -82.04, 31.01}, true
+	case 26766:
+		return Area{"USA - Georgia - SPCS - E", -83.47, 30.36, -80.77, 34.68}, true
+	case 26767:
+		return Area{"USA - Georgia - SPCS - W", -85.61, 30.62, -82.99, 35.01}, true
+	case 26768:
+		return Area{"USA - Idaho - SPCS - E", -113.24, 41.99, -111.04, 44.75}, true
+	case 26769:
+		return Area{"USA - Idaho - SPCS - C", -115.3, 41.99, -112.68, 45.7}, true
+	case 26770:
+		return Area{"USA - Idaho - SPCS - W", -117.24, 41.99, -114.32, 49.01}, true
+	case 26771:
+		return Area{"USA - Illinois - SPCS - E", -89.28, 37.06, -87.02, 42.5}, true
+	case 26772:
+		return Area{"USA - Illinois - SPCS - W", -91.52, 36.98, -88.93, 42.51}, true
+	case 26773:
+		return Area{"USA - Indiana - SPCS - E", -86.59, 37.95, -84.78, 41.77}, true
+	case 26774:
+		return Area{"USA - Indiana - SPCS - W", -88.06, 37.77, -86.24, 41.77}, true
+	case 26775:
+		return Area{"USA - Iowa - SPCS - N", -96.65, 41.85, -90.15, 43.51}, true
+	case 26776:
+		return Area{"USA - Iowa - SPCS - S", -95.94, 40.37, -90.14, 42.04}, true
+	case 26777:
+		return Area{"USA - Kansas - SPCS - N", -102.06, 38.52, -94.58, 40.01}, true
+	case 26778:
+		return Area{"USA - Kansas - SPCS - S", -102.05, 36.99, -94.6, 38.88}, true
+	case 26779:
+		return Area{"USA - Kentucky - SPCS - N", -85.96, 37.71, -82.47, 39.15}, true
+	case 26780:
+		return Area{"USA - Kentucky - SPCS - S", -89.57, 36.49, -81.95, 38.17}, true
+	case 26781:
+		return Area{"USA - Louisiana - SPCS - N", -94.05, 30.85, -90.86, 33.03}, true
+	case 26782:
+		return Area{"USA - Louisiana - SPCS27 - S", -93.94, 28.85, -88.75, 31.07}, true
+	case 26783:
+		return Area{"USA - Maine - SPCS - E", -70.03, 43.88, -66.91, 47.47}, true
+	case 26784:
+		return Area{"USA - Maine - SPCS - W", -71.09, 43.04, -69.26, 46.58}, true
+	case 26785:
+		return Area{"USA - Maryland", -79.49, 37.97, -74.97, 39.73}, true
+	case 26786:
+		return Area{"USA - Massachusetts - SPCS - mainland", -73.5, 41.46, -69.86, 42.89}, true
+	case 26787:
+		return Area{"USA - Massachusetts - SPCS - island", -70.91, 41.19, -69.89, 41.51}, true
+	case 26791:
+		return Area{"USA - Minnesota - SPCS - N", -97.22, 46.64, -89.49, 49.38}, true
+	case 26792:
+		return Area{"USA - Minnesota - SPCS - C", -96.86, 45.28, -92.29, 47.48}, true
+	case 26793:
+		return Area{"USA - Minnesota - SPCS - S", -96.85, 43.49, -91.21, 45.59}, true
+	case 26794:
+		return Area{"USA - Mississippi - SPCS - E", -89.97, 30.01, -88.09, 35.01}, true
+	case 26795:
+		return Area{"USA - Mississippi - SPCS - W", -91.65, 31, -89.37, 35.01}, true
+	case 26796:
+		return Area{"USA - Missouri - SPCS - E", -91.97, 35.98, -89.1, 40.61}, true
+	case 26797:
+		return Area{"USA - Missouri - SPCS - C", -93.79, 36.48, -91.41, 40.61}, true
+	case 26798:
+		return Area{"USA - Missouri - SPCS - W", -95.77, 36.48, -93.48, 40.59}, true
+	case 26917:
+		return Area{"North America - 84°W to 78°W and NAD83 by country", -84, 23.81, -78, 84}, true
+	case 27700:
+		return Area{"UK - Britain and UKCS 49°45'N to 61°N, 9°W to 2°E", -9.01, 49.75, 2.01, 61.01}, true
+	case 31257, 31284:
+		return Area{"Austria - west of 11°50'E", 9.53, 46.77, 11.84, 47.61}, true
+	case 31258, 31285:
+		return Area{"Austria - 11°50'E to 14°50'E", 11.83, 46.4, 14.84, 48.79}, true
+	case 31259, 31286:
+		return Area{"Austria - east of 14°50'E", 14.83, 46.56, 17.17, 49.02}, true
+	case 32024:
+		return Area{"USA - Oklahoma - SPCS - N", -103, 35.27, -94.42, 37.01}, true
+	case 102109:
+		return Area{"Slovenia", 13.38, 45.42, 16.61, 46.88}, true
+	case 102157:
+		return Area{"Kosovo", 20.01, 41.85, 21.8, 43.27}, true
+	case 102173:
+		return Area{"Poland", 14.14, 49, 24.15, 55.93}, true
+	}
+
+	switch {
+	case code > 3125 && code < 3139:
+		lon := float64(code - 3107)
+
+		return Area{fmt.Sprintf("Finland - %s to %s", longitude(lon-0.5), longitude(lon+0.5)), lon - 0.5, 59.3, lon + 0.5, 70.1}, true
+	case code > 3941 && code < 3951:
+		lat := float64(code - 3900)
+
+		return Area{fmt.Sprintf("France - %s to %s", latitude(lat-1), latitude(lat+1)), -9.86, lat - 1, 10.38, lat + 1}, true
+	case code > 26700 && code < 26723:
+		lon := float64(code-26700)*6 - 180
+
+		return Area{fmt.Sprintf("North America - %s to %s and NAD27 by country", longitude(lon-6), longitude(lon)), lon - 6, 14.92, lon, 84}, true
+	case code > 25827 && code < 25839:
+		lon := float64(code-25800)*6 - 180
+
+		return Area{fmt.Sprintf("Europe between %s and %s", longitude(lon-6), longitude(lon)), lon - 6, 32.88, lon, 84.73}, true
+	case code > 31465 && code < 31470:
+		lon := float64(code-31464) * 3
+
+		return Area{fmt.Sprintf("Germany - %s to %s", longitude(lon-1.5), longitude(lon+1.5)), max(lon-1.5, 5.87), 47.27, min(lon+1.5, 15.04), 55.09}, true
+	case code > 32600 && code < 32661:
+		lon := float64(code-32600)*6 - 180
+
+		return Area{fmt.Sprintf("World - N hemisphere - %s to %s", longitude(lon-6), longitude(lon)), lon - 6, 0, lon, 84}, true
+	case code > 32700 && code < 32761:
+		lon := float64(code-32700)*6 - 180
+
+		return Area{fmt.Sprintf("World - S hemisphere - %s to %s", longitude(lon-6), longitude(lon)), lon - 6, -80, lon, 0}, true
+	}
+
+	return Area{}, false
+}
+
+// spcs27TM returns a NAD27 State Plane zone in US survey feet with a false
+// easting of 500000 feet.
+func spcs27TM(lonf, latf, scale float64) CRS {
+	return Unit(TransverseMercator(EPSG(4267), lonf, latf, scale, 500000*USSurveyFoot, 0), USSurveyFoot)
+}
+
+// spcs27LCC returns a NAD27 State Plane zone in US survey feet.
+func spcs27LCC(lonf, latf, sp1, sp2, eastf float64) CRS {
+	return Unit(LambertConformalConic2SP(EPSG(4267), lonf, latf, sp1, sp2, eastf*USSurveyFoot, 0), USSurveyFoot)
+}
+
+func longitude(lon float64) string {
+	if lon < 0 {
+		return fmt.Sprintf("%g°W", -lon)
+	}
+
+	return fmt.Sprintf("%g°E", lon)
+}
+
+func latitude(lat float64) string {
+	if lat < 0 {
+		return fmt.Sprintf("%g°S", -lat)
+	}
+
+	return fmt.Sprintf("%g°N", lat)
+}
@@ -0,0 +1,210 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNAD27StatePlaneOrigin(t *testing.T) {
+	t.Parallel()
+
+	// The natural or false origin of each zone in NAD27 and its false easting
+	// and northing in US survey feet.
+	tests := []struct {
+		code        int
+		lon, lat    float64
+		east, north float64
+	}{
+		{26732, -142, 54, 500000, 0},
+		{26735, -154, 54, 500000, 0},
+		{26739, -170, 54, 500000, 0},
+		{26740, -176, 51, 3000000, 0},
+		{26747, -118.333333333333, 34.1333333333333, 4186692.58, 416926.74},
+		{26799, -118.333333333333, 34.1333333333333, 4186692.58, 4160926.74},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(EPSG(4267), EPSG(tt.code))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		east, north, _, err := tr.Transform(tt.lon, tt.lat, 0)
+		if err != nil || math.Abs(east-tt.east) > 1e-3 || math.Abs(north-tt.north) > 1e-3 {
+			t.Errorf("%d: %f, %f, %v, want %f, %f", tt.code, east, north, err, tt.east, tt.north)
+		}
+	}
+}
+
+func TestEPSGHelmert(t *testing.T) {
+	t.Parallel()
+
+	// Geocentric translations (EPSG method 9603), EPSG Guidance Note 7-2,
+	// 2.4.3.1: WGS 84 to ED50.
+	ed50 := Geographic(HelmertPV(-84.87, -96.49, -116.95, 0, 0, 0, 0), NewSpheroid(6378388, 297))
+
+	tr, err := NewTransformer(EPSG(4326), ed50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lon, lat, h, err := tr.Transform(2+7/60.0+46.38/3600, 53+48/60.0+33.82/3600, 73)
+	if err != nil || math.Abs(lon-(2+7/60.0+51.477/3600))*3600 > 1e-3 ||
+		math.Abs(lat-(53+48/60.0+36.565/3600))*3600 > 1e-3 || math.Abs(h-28.02) > 0.01 {
+		t.Errorf("9603: %.9f, %.9f, %.3f, %v, want 2°07'51.477\", 53°48'36.565\", 28.02 m", lon, lat, h, err)
+	}
+
+	// The sets of the EPSG registry in their published convention. Position
+	// Vector sets (9606) must equal the Coordinate Frame sets (9607) with
+	// negated rotations. NAD27 and OSGB 1936 use them only if their grids are
+	// missing.
+	tests := []struct {
+		code     int
+		crs      CRS
+		lon, lat float64
+	}{
+		{4156, Geographic(HelmertCF(589, 76, 480, 0, 0, 0, 0), NewSpheroid(6377397.155, 299.1528128)), 15, 50},
+		{4188, Geographic(HelmertCF(482.5, -130.6, 564.6, 1.042, 0.214, 0.631, 8.15), NewSpheroid(6377563.396, 299.3249646)), -6.5, 54.5},
+		{4230, Geographic(HelmertCF(-87, -98, -121, 0, 0, 0, 0), NewSpheroid(6378388, 297)), 5, 50},
+		{4267, Geographic(HelmertCF(-8, 160, 176, 0, 0, 0, 0), NewSpheroid(6378206.4, 294.978698213898)), -100, 40},
+		{4277, Geographic(HelmertCF(446.448, -125.157, 542.06, -0.15, -0.247, -0.842, -20.489), NewSpheroid(6377563.396, 299.3249646)), -2, 52},
+		{4299, Geographic(HelmertCF(482.5, -130.6, 564.6, 1.042, 0.214, 0.631, 8.15), NewSpheroid(6377340.189, 299.3249646)), -8, 53},
+		{4312, Geographic(HelmertCF(577.326, 90.129, 463.919, -5.137, -1.474, -5.297, 2.4232), NewSpheroid(6377397.155, 299.1528128)), 14, 47.5},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(EPSG(tt.code), EPSG(4326))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lon, lat, h, status, err := tr.TransformStatus(tt.lon, tt.lat, 0)
+		if err != nil {
+			t.Fatalf("%d: %v", tt.code, err)
+		}
+
+		if tt.code == 4267 || tt.code == 4277 {
+			if status != StatusFallback {
+				continue
+			}
+		}
+
+		wlon, wlat, wh := Transform(tt.crs, EPSG(4326))(tt.lon, tt.lat, 0)
+
+		if math.Abs(lon-wlon) > 1e-9 || math.Abs(lat-wlat) > 1e-9 || math.Abs(h-wh) > 1e-4 {
+			t.Errorf("%d: %.9f, %.9f, %.4f, want %.9f, %.9f, %.4f", tt.code, lon, lat, h, wlon, wlat, wh)
+		}
+	}
+}
+
+func TestEPSGArea(t *testing.T) {
+	t.Parallel()
+
+	for code := 1; code < 1000000; code++ {
+		crs := EPSG(code)
+		if _, ok := crs.(errorCRS); ok || code == 4978 {
+			continue
+		}
+
+		if _, ok := AreaOf(crs); !ok {
+			t.Errorf("%d: no area of use", code)
+		}
+	}
+
+	tr, err := NewTransformer(EPSG(4267), Strict(EPSG(26749)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := tr.Transform(-100, 40, 0); !errors.Is(err, ErrOutsideDomain) {
+		t.Errorf("26749: error %v, want ErrOutsideDomain", err)
+	}
+
+	if _, _, _, err := tr.Transform(-112, 34, 0); err != nil {
+		t.Errorf("26749: %v", err)
+	}
+
+	if _, _, _, err := tr.Inverse().Transform(3000000, 1000000, 0); !errors.Is(err, ErrOutsideDomain) {
+		t.Errorf("26749 inverse: error %v, want ErrOutsideDomain", err)
+	}
+}
+
+// resetEPSG removes the CRSs from the store after the test, so that grids
+// loaded from a temporary directory are not kept.
+func resetEPSG(t *testing.T, codes ...int) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		for _, code := range codes {
+			crsStore.Delete(code)
+		}
+	})
+}
+
+func TestOSTN15(t *testing.T) {
+	resetEPSG(t, 4277, 27700, 7405)
+
+	fallback := TransverseMercator(Geographic(HelmertPV(446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489),
+		NewSpheroid(6377563.396, 299.3249646)), -2, 49, 0.9996012717, 400000, -100000)
+
+	tests := []struct {
+		name   string
+		grid   bool
+		status Status
+	}{
+		{"fallback", false, StatusFallback},
+		{"grid", true, StatusOK},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		t.Setenv(GridPathEnv, dir)
+
+		if tt.grid {
+			// The grid shifts 2" north and 4" east.
+			records := make([][4]float32, 9)
+			for i := range records {
+				records[i] = [4]float32{2, -4, 0.01, 0.01}
+			}
+
+			crs, err := NewNTv2(NTv2Header{}, []NTv2Subgrid{{
+				Name: "OSTN15", SLat: 52 * 3600, NLat: 53 * 3600, ELong: 3600, WLong: 2 * 3600,
+				LatInc: 1800, LongInc: 1800, Records: records,
+			}}, NewSpheroid(6377563.396, 299.3249646), EPSG(4326))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := WriteNTv2File(filepath.Join(dir, "OSTN15_NTv2_OSGBtoETRS.gsb"), crs); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tr, err := NewTransformer(EPSG(4326), EPSG(27700))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		x, y, _, status, err := tr.TransformStatus(-1.5, 52.5, 0)
+		if err != nil || status != tt.status {
+			t.Fatalf("%s: status %s, %v, want %s", tt.name, status, err, tt.status)
+		}
+
+		wx, wy, _ := Transform(EPSG(4326), fallback)(-1.5, 52.5, 0)
+		if tt.grid {
+			wx, wy, _ = Transform(EPSG(4277), EPSG(27700))(-1.5-4.0/3600, 52.5-2.0/3600, 0)
+		}
+
+		if math.Abs(x-wx) > 1e-4 || math.Abs(y-wy) > 1e-4 {
+			t.Errorf("%s: %.4f, %.4f, want %.4f, %.4f", tt.name, x, y, wx, wy)
+		}
+
+		if pipeline := NewPipeline(EPSG(4326), EPSG(27700)).String(); strings.Contains(pipeline, "Helmert") == tt.grid {
+			t.Errorf("%s: pipeline %s", tt.name, pipeline)
+		}
+	}
+}
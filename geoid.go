@@ -0,0 +1,719 @@
+//nolint:varnamelen,gomnd,nonamedreturns,ireturn
+package wgs84
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// geoid is a vertical CRS of orthometric heights. ToBase adds the undulation.
+type geoid struct {
+	format string
+	name   string
+	base   CRS
+	grid   *geoidGrid
+}
+
+// geoidGrid contains the undulations in meters row by row from south to north
+// and from west to east. Missing values are NaN.
+type geoidGrid struct {
+	west, south    float64
+	lonInc, latInc float64
+	cols, rows     int
+	values         []float32
+}
+
+func newGeoid(format string, grid *geoidGrid, base CRS) geoid {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return geoid{
+		format: format,
+		base:   base,
+		grid:   grid,
+	}
+}
+
+func (g geoid) String() string {
+	return fmt.Sprintf("Geoid(%s)", strings.TrimSpace(g.format+" "+g.name))
+}
+
+func (g geoid) describe(fromBase bool) string {
+	return inverse(strings.TrimSpace("Geoid "+g.format+" "+g.name), fromBase)
+}
+
+func (g geoid) Base() CRS {
+	return g.base
+}
+
+func (g geoid) Spheroid() Spheroid {
+	return g.base.Spheroid()
+}
+
+func (g geoid) ToBase(lon, lat, h float64) (float64, float64, float64) {
+	return lon, lat, h + g.grid.undulation(lon, lat)
+}
+
+func (g geoid) FromBase(lon, lat, h float64) (float64, float64, float64) {
+	return lon, lat, h - g.grid.undulation(lon, lat)
+}
+
+func (g geoid) SafeToBase(lon, lat, h float64) (float64, float64, float64, error) {
+	n, err := g.undulation(lon, lat)
+
+	return lon, lat, h + n, err
+}
+
+func (g geoid) SafeFromBase(lon, lat, h float64) (float64, float64, float64, error) {
+	n, err := g.undulation(lon, lat)
+
+	return lon, lat, h - n, err
+}
+
+func (g geoid) undulation(lon, lat float64) (float64, error) {
+	n := g.grid.undulation(lon, lat)
+	if math.IsNaN(n) && !math.IsNaN(lon) && !math.IsNaN(lat) {
+		return n, fmt.Errorf("%w: %f, %f is outside of geoid %s", ErrOutsideDomain, lon, lat, g.name)
+	}
+
+	return n, nil
+}
+
+func (g geoid) ToBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		n, err := g.undulation(xs[i], ys[i])
+		if err != nil {
+			return err
+		}
+
+		zs[i] += n
+	}
+
+	return nil
+}
+
+func (g geoid) FromBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		n, err := g.undulation(xs[i], ys[i])
+		if err != nil {
+			return err
+		}
+
+		zs[i] -= n
+	}
+
+	return nil
+}
+
+// global reports whether the grid covers all longitudes.
+func (g *geoidGrid) global() bool {
+	return float64(g.cols)*g.lonInc >= 360-g.lonInc/2
+}
+
+// undulation interpolates the geoid undulation bilinearly. It is NaN outside
+// of the grid and next to missing values.
+func (g *geoidGrid) undulation(lon, lat float64) float64 {
+	fx := snap((lon - g.west) / g.lonInc)
+	fy := snap((lat - g.south) / g.latInc)
+
+	if fy < 0 || fy > float64(g.rows-1) {
+		return math.NaN()
+	}
+
+	// The columns of global grids wrap around, the last column may repeat the
+	// first one.
+	period := int(math.Round(360 / g.lonInc))
+
+	fx = math.Mod(fx, float64(period))
+	if fx < 0 {
+		fx += float64(period)
+	}
+
+	if !g.global() && fx > float64(g.cols-1) {
+		return math.NaN()
+	}
+
+	col, row := min(int(fx), g.cols-1), min(int(fy), g.rows-2)
+	next := col + 1
+
+	if next >= period || next >= g.cols {
+		next = col
+		if g.global() {
+			next = 0
+		}
+	}
+
+	x, y := fx-float64(col), fy-float64(row)
+
+	v00 := float64(g.values[row*g.cols+col])
+	v10 := float64(g.values[row*g.cols+next])
+	v01 := float64(g.values[(row+1)*g.cols+col])
+	v11 := float64(g.values[(row+1)*g.cols+next])
+
+	return v00*(1-x)*(1-y) + v10*x*(1-y) + v01*(1-x)*y + v11*x*y
+}
+
+// snap ignores rounding errors of coordinates on the nodes.
+func snap(f float64) float64 {
+	if r := math.Round(f); math.Abs(f-r) < 1e-9 {
+		return r
+	}
+
+	return f
+}
+
+// GTXFile loads a geoid in the GTX format from the file system. See GTX.
+func GTXFile(path string, base CRS) (CRS, error) {
+	return geoidFile(path, base, readGTX)
+}
+
+// GTX reads a geoid in the GTX format of NOAA. The base CRS has ellipsoidal
+// heights and defaults to EPSG 4326.
+func GTX(reader io.Reader, base CRS) (CRS, error) {
+	grid, err := readGTX(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeoid("GTX", grid, base), nil
+}
+
+// readGTX reads the big endian header (south, west, latitude and longitude
+// increments as float64, rows and columns as int32) and the undulations as
+// float32 from south to north and from west to east. -88.8888 is missing.
+func readGTX(reader io.Reader) (*geoidGrid, error) {
+	head := make([]byte, 40)
+
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return nil, err
+	}
+
+	order := binary.BigEndian
+
+	g := &geoidGrid{
+		south:  toFloat(order, head[0:8]),
+		west:   toFloat(order, head[8:16]),
+		latInc: toFloat(order, head[16:24]),
+		lonInc: toFloat(order, head[24:32]),
+		rows:   int(int32(order.Uint32(head[32:36]))),
+		cols:   int(int32(order.Uint32(head[36:40]))),
+	}
+
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("invalid gtx header: %w", err)
+	}
+
+	g.values = make([]float32, g.cols*g.rows)
+	row := make([]byte, 4*g.cols)
+
+	for r := 0; r < g.rows; r++ {
+		if _, err := io.ReadFull(reader, row); err != nil {
+			return nil, err
+		}
+
+		for c := 0; c < g.cols; c++ {
+			v := toFloat32(order, row[c*4:])
+			if v == -88.8888 {
+				v = float32(math.NaN())
+			}
+
+			g.values[r*g.cols+c] = v
+		}
+	}
+
+	return g, nil
+}
+
+func (g *geoidGrid) validate() error {
+	if g.cols < 2 || g.rows < 2 || g.cols > 1<<20 || g.rows > 1<<20 || !(g.lonInc > 0) || !(g.latInc > 0) {
+		return fmt.Errorf("%d columns, %d rows, increments %f, %f", g.cols, g.rows, g.latInc, g.lonInc)
+	}
+
+	if g.west >= 180 {
+		g.west -= 360
+	}
+
+	return nil
+}
+
+// EGM96File loads the EGM96 geoid in the format of NGA (WW15MGH.GRD) from the
+// file system. See EGM96.
+func EGM96File(path string, base CRS) (CRS, error) {
+	return geoidFile(path, base, readEGM96)
+}
+
+// EGM96 reads a geoid in the ASCII grid format of NGA, e.g. WW15MGH.GRD. The
+// base CRS has ellipsoidal heights and defaults to EPSG 4326.
+func EGM96(reader io.Reader, base CRS) (CRS, error) {
+	grid, err := readEGM96(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeoid("EGM96", grid, base), nil
+}
+
+func readEGM96(reader io.Reader) (*geoidGrid, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanWords)
+
+	next := func() (float64, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return 0, err
+			}
+
+			return 0, io.ErrUnexpectedEOF
+		}
+
+		return strconv.ParseFloat(scanner.Text(), 64)
+	}
+
+	var head [6]float64
+
+	for i := range head {
+		v, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("invalid egm96 header: %w", err)
+		}
+
+		head[i] = v
+	}
+
+	south, north, west, east, latInc, lonInc := head[0], head[1], head[2], head[3], head[4], head[5]
+
+	g := &geoidGrid{
+		south:  south,
+		west:   west,
+		latInc: latInc,
+		lonInc: lonInc,
+	}
+
+	if latInc > 0 && lonInc > 0 {
+		g.rows = int(math.Round((north-south)/latInc)) + 1
+		g.cols = int(math.Round((east-west)/lonInc)) + 1
+	}
+
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("invalid egm96 header: %w", err)
+	}
+
+	g.values = make([]float32, g.cols*g.rows)
+
+	for r := g.rows - 1; r >= 0; r-- {
+		for c := 0; c < g.cols; c++ {
+			v, err := next()
+			if err != nil {
+				return nil, fmt.Errorf("invalid egm96 value: %w", err)
+			}
+
+			g.values[r*g.cols+c] = float32(v)
+		}
+	}
+
+	return g, nil
+}
+
+// EGM2008File loads the EGM2008 geoid in the format of NGA from the file
+// system. See EGM2008.
+func EGM2008File(path string, base CRS) (CRS, error) {
+	return geoidFile(path, base, readEGM2008)
+}
+
+// EGM2008 reads a global geoid in the binary format of NGA. The base CRS has
+// ellipsoidal heights and defaults to EPSG 4326.
+func EGM2008(reader io.Reader, base CRS) (CRS, error) {
+	grid, err := readEGM2008(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeoid("EGM2008", grid, base), nil
+}
+
+func readEGM2008(reader io.Reader) (*geoidGrid, error) {
+	marker := make([]byte, 4)
+
+	if _, err := io.ReadFull(reader, marker); err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+
+	if n := binary.LittleEndian.Uint32(marker); n == 0 || n%4 != 0 || n > 1<<22 {
+		order = binary.BigEndian
+	}
+
+	size := int(order.Uint32(marker))
+	if size == 0 || size%4 != 0 || size > 1<<22 {
+		return nil, fmt.Errorf("invalid egm2008 record length %d", size)
+	}
+
+	g := &geoidGrid{
+		cols: size / 4,
+		west: 0,
+	}
+
+	g.rows = g.cols/2 + 1
+	g.lonInc = 360 / float64(g.cols)
+	g.latInc = 180 / float64(g.rows-1)
+	g.south = -90
+
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("invalid egm2008 grid: %w", err)
+	}
+
+	g.values = make([]float32, g.cols*g.rows)
+	row := make([]byte, size+8)
+
+	for r := g.rows - 1; r >= 0; r-- {
+		// The leading marker of the first row has been read already.
+		buf := row[4:]
+		if r < g.rows-1 {
+			buf = row
+		}
+
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+
+		for c := 0; c < g.cols; c++ {
+			g.values[r*g.cols+c] = toFloat32(order, row[4+c*4:])
+		}
+	}
+
+	return g, nil
+}
+
+// ISGFile loads a geoid in the ISG format from the file system. See ISG.
+func ISGFile(path string, base CRS) (CRS, error) {
+	return geoidFile(path, base, readISG)
+}
+
+// ISG reads a geoid in the ISG format 1.0 or 2.0. The base CRS has ellipsoidal
+// heights and defaults to EPSG 4326.
+func ISG(reader io.Reader, base CRS) (CRS, error) {
+	grid, err := readISG(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeoid("ISG", grid, base), nil
+}
+
+func readISG(reader io.Reader) (*geoidGrid, error) {
+	buf := bufio.NewReader(reader)
+	head := map[string]string{}
+	inHead := false
+
+	for {
+		text, err := buf.ReadString('\n')
+		if err != nil && (err != io.EOF || text == "") {
+			if err == io.EOF {
+				return nil, errors.New("invalid isg: end_of_head not found")
+			}
+
+			return nil, err
+		}
+
+		line := strings.TrimSpace(text)
+
+		if strings.HasPrefix(line, "begin_of_head") {
+			inHead = true
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "end_of_head") {
+			break
+		}
+
+		if !inHead {
+			continue
+		}
+
+		i := strings.IndexAny(line, ":=")
+		if i < 0 {
+			continue
+		}
+
+		head[strings.ToLower(strings.TrimSpace(line[:i]))] = strings.TrimSpace(line[i+1:])
+	}
+
+	if t := head["data format"]; t != "" && t != "grid" {
+		return nil, fmt.Errorf("unsupported isg data format %q", t)
+	}
+
+	if t := head["coord type"]; t != "" && t != "geodetic" {
+		return nil, fmt.Errorf("unsupported isg coord type %q", t)
+	}
+
+	dms := head["coord units"] == "dms"
+
+	var (
+		err  error
+		nums = map[string]float64{}
+	)
+
+	for _, key := range []string{"lat min", "lat max", "lon min", "lon max", "delta lat", "delta lon"} {
+		if nums[key], err = parseISGAngle(head[key], dms); err != nil {
+			return nil, fmt.Errorf("invalid isg %s: %w", key, err)
+		}
+	}
+
+	for _, key := range []string{"nrows", "ncols"} {
+		if nums[key], err = strconv.ParseFloat(head[key], 64); err != nil {
+			return nil, fmt.Errorf("invalid isg %s: %w", key, err)
+		}
+	}
+
+	nodata := math.NaN()
+
+	if v, ok := head["nodata"]; ok {
+		if nodata, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid isg nodata: %w", err)
+		}
+	}
+
+	g := &geoidGrid{
+		south:  nums["lat min"],
+		west:   nums["lon min"],
+		latInc: nums["delta lat"],
+		lonInc: nums["delta lon"],
+		rows:   int(nums["nrows"]),
+		cols:   int(nums["ncols"]),
+	}
+
+	// The bounds of cell registered grids are the borders of the cells.
+	if math.Abs(nums["lat max"]-nums["lat min"]-float64(g.rows)*g.latInc) < g.latInc/2 {
+		g.south += g.latInc / 2
+		g.west += g.lonInc / 2
+	}
+
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("invalid isg header: %w", err)
+	}
+
+	southToNorth := strings.HasPrefix(strings.ToUpper(head["data ordering"]), "S-TO-N")
+
+	g.values = make([]float32, g.cols*g.rows)
+	scanner := bufio.NewScanner(buf)
+	scanner.Split(bufio.ScanWords)
+
+	for i := 0; i < g.rows; i++ {
+		r := g.rows - 1 - i
+		if southToNorth {
+			r = i
+		}
+
+		for c := 0; c < g.cols; c++ {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			v, err := strconv.ParseFloat(scanner.Text(), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid isg value: %w", err)
+			}
+
+			if v == nodata {
+				v = math.NaN()
+			}
+
+			g.values[r*g.cols+c] = float32(v)
+		}
+	}
+
+	return g, nil
+}
+
+// parseISGAngle parses decimal degrees or degrees, minutes and seconds like
+// 45°30'00".
+func parseISGAngle(s string, dms bool) (float64, error) {
+	if !dms {
+		return strconv.ParseFloat(s, 64)
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+'
+	})
+
+	if len(fields) == 0 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid angle %q", s)
+	}
+
+	var v float64
+
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		v += math.Abs(n) / math.Pow(60, float64(i))
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(s), "-") {
+		v = -v
+	}
+
+	return v, nil
+}
+
+// geoidFile reads a geoid from the file system.
+func geoidFile(path string, base CRS, read func(io.Reader) (*geoidGrid, error)) (CRS, error) {
+	data, err := openGeoid(path, base, read, func(name string) (io.ReadCloser, error) {
+		return openGrid(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func openGeoid(name string, base CRS, read func(io.Reader) (*geoidGrid, error), open func(string) (io.ReadCloser, error)) (geoid, error) {
+	file, err := open(name)
+	if err != nil {
+		return geoid{}, err
+	}
+
+	defer file.Close()
+
+	grid, err := read(bufio.NewReaderSize(file, 1<<16))
+	if err != nil {
+		return geoid{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	data := newGeoid(geoidFormat(name), grid, base)
+	data.name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+
+	return data, nil
+}
+
+func geoidFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gtx":
+		return "GTX"
+	case ".isg":
+		return "ISG"
+	case ".grd":
+		return "EGM96"
+	default:
+		return "EGM2008"
+	}
+}
+
+// Geoid returns a geoid, which is loaded on its first use. The format is chosen
+// by the extension: .gtx, .isg, .grd (EGM96) or else EGM2008.
+func (r *GridResolver) Geoid(name string, base CRS) CRS {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	read := readEGM2008
+
+	switch geoidFormat(name) {
+	case "GTX":
+		read = readGTX
+	case "ISG":
+		read = readISG
+	case "EGM96":
+		read = readEGM96
+	}
+
+	return lazyGeoid{&lazyGeoidState{
+		name:     strings.TrimSuffix(name, filepath.Ext(name)),
+		base:     base,
+		resolver: r,
+		open: func() (geoid, error) {
+			return openGeoid(name, base, read, func(name string) (io.ReadCloser, error) {
+				return r.Open(name)
+			})
+		},
+	}}
+}
+
+// lazyGeoid is a geoid that is loaded on its first use. If it is missing, the
+// heights are NaN.
+type lazyGeoid struct {
+	*lazyGeoidState
+}
+
+type lazyGeoidState struct {
+	name     string
+	base     CRS
+	resolver *GridResolver
+	open     func() (geoid, error)
+	cache    gridCache
+}
+
+func (l lazyGeoid) load() CRS {
+	return l.cache.load(l.resolver, func() (CRS, bool) {
+		data, err := l.open()
+		if err != nil {
+			return errorCRS{err: err}, false
+		}
+
+		return data, true
+	})
+}
+
+func (l lazyGeoid) String() string {
+	return fmt.Sprint(l.load())
+}
+
+func (l lazyGeoid) describe(fromBase bool) string {
+	if d, ok := l.load().(describer); ok {
+		return d.describe(fromBase)
+	}
+
+	return inverse("Geoid "+l.name, fromBase)
+}
+
+func (l lazyGeoid) Base() CRS {
+	return l.base
+}
+
+func (l lazyGeoid) Spheroid() Spheroid {
+	return l.base.Spheroid()
+}
+
+func (l lazyGeoid) ToBase(lon, lat, h float64) (float64, float64, float64) {
+	crs := l.load()
+	if _, ok := crs.(errorCRS); ok {
+		return lon, lat, math.NaN()
+	}
+
+	return crs.ToBase(lon, lat, h)
+}
+
+func (l lazyGeoid) FromBase(lon, lat, h float64) (float64, float64, float64) {
+	crs := l.load()
+	if _, ok := crs.(errorCRS); ok {
+		return lon, lat, math.NaN()
+	}
+
+	return crs.FromBase(lon, lat, h)
+}
+
+func (l lazyGeoid) SafeToBase(lon, lat, h float64) (float64, float64, float64, error) {
+	return Step{CRS: l.load()}.call(lon, lat, h)
+}
+
+func (l lazyGeoid) SafeFromBase(lon, lat, h float64) (float64, float64, float64, error) {
+	return Step{CRS: l.load(), FromBase: true}.call(lon, lat, h)
+}
@@ -0,0 +1,184 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMissingGeoid(t *testing.T) {
+	t.Parallel()
+
+	geoid := NewGridResolver().Geoid("missing.gtx", EPSG(4326))
+
+	tests := []struct {
+		name string
+		from CRS
+		to   CRS
+	}{
+		{"to geoid", EPSG(4326), geoid},
+		{"from geoid", geoid, EPSG(4326)},
+	}
+
+	for _, tt := range tests {
+		x, y, h := Transform(tt.from, tt.to)(10, 50, 100)
+		if math.IsNaN(x) || math.IsNaN(y) || !math.IsNaN(h) {
+			t.Errorf("%s: %f, %f, %f, want horizontal coordinates and NaN height", tt.name, x, y, h)
+		}
+
+		if _, err := NewTransformer(tt.from, tt.to); !errors.Is(err, ErrGridMissing) {
+			t.Errorf("%s: error %v, want ErrGridMissing", tt.name, err)
+		}
+	}
+}
+
+func TestCompoundChainError(t *testing.T) {
+	t.Parallel()
+
+	geoid := NewGridResolver().Geoid("missing.gtx", EPSG(4326))
+
+	tests := []struct {
+		name string
+		crs  CRS
+		err  error
+	}{
+		{"geoid", Compound(EPSG(3857), geoid), ErrGridMissing},
+		{"horizontal", Compound(EPSG(1), EPSG(5773)), ErrUnknownCode},
+	}
+
+	for _, tt := range tests {
+		for _, pair := range [][2]CRS{{EPSG(4326), tt.crs}, {tt.crs, EPSG(4326)}} {
+			if _, err := NewTransformer(pair[0], pair[1]); !errors.Is(err, tt.err) {
+				t.Errorf("%s: error %v, want %v", tt.name, err, tt.err)
+			}
+		}
+	}
+}
+
+func TestGeoidFiles(t *testing.T) {
+	t.Parallel()
+
+	gtx, err := GTXFile("testdata/geoid.gtx", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isg, err := ISGFile("testdata/geoid.isg", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testdata/geoid.gtx has 3x3 nodes between 45N 75.5W and 45.5N 75W with
+	// the undulation 0.5 m/° of latitude + 0.2 m/° of longitude.
+	// testdata/geoid.isg (ISG 2.0, dms) has 3x3 nodes between 50N 8E and 50.5N
+	// 8.5E with 47 m + 0.8 m/° of latitude - 0.6 m/° of longitude.
+	tests := []struct {
+		name     string
+		crs      CRS
+		lon, lat float64
+		n        float64
+	}{
+		{"GTX", gtx, -75, 45, 7.5},
+		{"GTX", gtx, -75.5, 45.5, 7.65},
+		{"GTX", gtx, -75.125, 45.125, 7.5375},
+		{"GTX", gtx, -75.3, 45.4, 7.64},
+		{"ISG", isg, 8, 50, 47},
+		{"ISG", isg, 8.5, 50.5, 47.1},
+		{"ISG", isg, 8.125, 50.375, 47.225},
+		{"ISG", isg, 8.4, 50.1, 46.84},
+	}
+
+	for _, tt := range tests {
+		lon, lat, h := tt.crs.ToBase(tt.lon, tt.lat, 100)
+		if lon != tt.lon || lat != tt.lat || math.Abs(h-100-tt.n) > 1e-4 {
+			t.Errorf("%s %f, %f: %f, %f, %f, want undulation %f", tt.name, tt.lon, tt.lat, lon, lat, h, tt.n)
+		}
+	}
+
+	for _, crs := range []CRS{gtx, isg} {
+		step := Step{CRS: crs}
+		if _, _, _, err := step.call(0, 0, 100); !errors.Is(err, ErrOutsideDomain) {
+			t.Errorf("%s: error %v, want ErrOutsideDomain", crs, err)
+		}
+	}
+}
+
+func TestGeoidSlice(t *testing.T) {
+	t.Parallel()
+
+	gtx, err := GTXFile("testdata/geoid.gtx", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to CRS
+	}{
+		{"to geoid", EPSG(4326), gtx},
+		{"from geoid", gtx, EPSG(4326)},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, lon := range []float64{-75.2, -76} {
+			x, y, z, err := tr.Transform(lon, 45.2, 100)
+
+			xs, ys, zs := []float64{lon}, []float64{45.2}, []float64{100}
+			serr := tr.Slice(xs, ys, zs)
+
+			if (serr == nil) != (err == nil) || (err == nil && (xs[0] != x || ys[0] != y || zs[0] != z)) {
+				t.Errorf("%s %f: Slice %f, %f, %f, %v, Transform %f, %f, %f, %v", tt.name, lon,
+					xs[0], ys[0], zs[0], serr, x, y, z, err)
+			}
+
+			if lon == -76 && (!errors.Is(serr, ErrOutsideDomain) || xs[0] != lon || ys[0] != 45.2 || zs[0] != 100) {
+				t.Errorf("%s %f: %f, %f, %f, %v, want unchanged and ErrOutsideDomain", tt.name, lon, xs[0], ys[0], zs[0], serr)
+			}
+		}
+	}
+}
+
+func TestCompoundEPSG(t *testing.T) {
+	resetEPSG(t, 5783, 5555, 5556)
+
+	raw, err := os.ReadFile("testdata/geoid.isg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "GCG2011.isg"), raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(GridPathEnv, dir)
+
+	tr, err := NewTransformer(EPSG(4326), EPSG(5555))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, y, h, err := tr.Transform(8.25, 50.25, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The undulation at the centre of testdata/geoid.isg is 47.05 m.
+	wx, wy, wh := Transform(EPSG(4326), EPSG(25832))(8.25, 50.25, 100)
+	if wh -= 47.05; math.Abs(x-wx) > 1e-6 || math.Abs(y-wy) > 1e-6 || math.Abs(h-wh) > 1e-6 {
+		t.Errorf("%.6f, %.6f, %.6f, want %.6f, %.6f, %.6f", x, y, h, wx, wy, wh)
+	}
+
+	lon, lat, h, err := tr.Inverse().Transform(x, y, h)
+	if err != nil || math.Abs(lon-8.25) > 1e-9 || math.Abs(lat-50.25) > 1e-9 || math.Abs(h-100) > 1e-6 {
+		t.Errorf("inverse: %.9f, %.9f, %.6f, %v", lon, lat, h, err)
+	}
+}
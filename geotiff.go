@@ -0,0 +1,807 @@
+//nolint:varnamelen,gomnd,cyclop,funlen,gocognit
+package wgs84
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeoTIFFFile loads a horizontal offset grid in the GeoTIFF format of PROJ
+// from the file system. See GeoTIFF.
+func GeoTIFFFile(path string, spheroid Spheroid, base CRS) (CRS, error) {
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readGeoTIFF(r[0], spheroid, base)
+	}, path)
+}
+
+// GeoTIFF reads a horizontal offset grid in the GeoTIFF format of PROJ-data.
+// The base CRS defaults to EPSG 4326.
+func GeoTIFF(reader io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readGeoTIFF(reader, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func readGeoTIFF(reader io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	r, err := readerAt(reader)
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	t, offset, err := newTIFF(r)
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	data := ntv2{
+		format:   "GeoTIFF",
+		spheroid: spheroid,
+		base:     base,
+	}
+
+	var (
+		grids []*ntv2Grid
+		first gdalMetadata
+		seen  = map[uint64]bool{}
+	)
+
+	for offset != 0 {
+		if seen[offset] {
+			return ntv2{}, errors.New("invalid tiff: cyclic image directories")
+		}
+
+		seen[offset] = true
+
+		ifd, next, err := t.ifd(offset)
+		if err != nil {
+			return ntv2{}, err
+		}
+
+		offset = next
+
+		// Skip overviews and masks.
+		if ifd.uint(tagNewSubfileType, 0)&5 != 0 {
+			continue
+		}
+
+		meta, err := parseGDALMetadata(ifd.string(tagGDALMetadata))
+		if err != nil {
+			return ntv2{}, err
+		}
+
+		if len(grids) == 0 {
+			first = meta
+		} else {
+			meta = meta.inherit(first)
+		}
+
+		grid, err := t.grid(ifd, meta, spheroid)
+		if err != nil {
+			return ntv2{}, fmt.Errorf("invalid geotiff subgrid %d: %w", len(grids), err)
+		}
+
+		if grid.name == "" {
+			grid.name = strconv.Itoa(len(grids))
+		}
+
+		grids = append(grids, grid)
+	}
+
+	if len(grids) == 0 {
+		return ntv2{}, errors.New("invalid geotiff: no grid")
+	}
+
+	data.name = first.get("grid_name", "")
+	data.numFile = int32(len(grids))
+
+	switch first.get("interpolation_method", "bilinear") {
+	case "biquadratic":
+		data.interpolation = InterpolationBiquadratic
+	case "bicubic":
+		data.interpolation = InterpolationBicubic
+	}
+
+	// Subgrids are children of the smallest previous grid containing them.
+	for i, g := range grids {
+		var parent *ntv2Grid
+
+		for _, p := range grids[:i] {
+			if p.sLat <= g.sLat && p.nLat >= g.nLat && p.eLong <= g.eLong && p.wLong >= g.wLong &&
+				(parent == nil || (p.nLat-p.sLat)*(p.wLong-p.eLong) < (parent.nLat-parent.sLat)*(parent.wLong-parent.eLong)) {
+				parent = p
+			}
+		}
+
+		if parent == nil {
+			g.parent = "NONE"
+			data.grids = append(data.grids, g)
+
+			continue
+		}
+
+		g.parent = parent.name
+		parent.children = append(parent.children, g)
+	}
+
+	return data, nil
+}
+
+// grid reads the raster of an image as ntv2Grid.
+func (t tiff) grid(ifd tiffIFD, meta gdalMetadata, spheroid Spheroid) (*ntv2Grid, error) {
+	if typ := meta.get("TYPE", "HORIZONTAL_OFFSET"); typ != "HORIZONTAL_OFFSET" {
+		return nil, fmt.Errorf("unsupported TYPE %q", typ)
+	}
+
+	width, height := int(ifd.uint(tagImageWidth, 0)), int(ifd.uint(tagImageLength, 0))
+	if width < 2 || height < 2 || width*height > 1<<28 {
+		return nil, fmt.Errorf("invalid size %dx%d", width, height)
+	}
+
+	scale, tiepoint := ifd.floats(tagModelPixelScale), ifd.floats(tagModelTiepoint)
+	if len(scale) < 2 || len(tiepoint) < 6 || scale[0] <= 0 || scale[1] <= 0 {
+		return nil, errors.New("missing ModelPixelScaleTag or ModelTiepointTag")
+	}
+
+	west := tiepoint[3] - tiepoint[0]*scale[0]
+	north := tiepoint[4] + tiepoint[1]*scale[1]
+
+	// The tie point is the corner of the pixel unless GTRasterTypeGeoKey is
+	// RasterPixelIsPoint.
+	if geoKey(ifd.uints(tagGeoKeyDirectory), 1025) != 2 {
+		west += scale[0] / 2
+		north -= scale[1] / 2
+	}
+
+	samples, err := t.raster(ifd, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	h := eastHeader{
+		west:   west,
+		south:  north - float64(height-1)*scale[1],
+		lonInc: scale[0],
+		latInc: scale[1],
+		cols:   width,
+		rows:   height,
+	}
+
+	latIdx := meta.sample("latitude_offset", 0)
+	lonIdx := meta.sample("longitude_offset", 1)
+
+	if latIdx >= len(samples) || lonIdx >= len(samples) {
+		return nil, fmt.Errorf("missing offset samples: %d samples", len(samples))
+	}
+
+	lat, err := meta.seconds(samples, latIdx, h, spheroid, false)
+	if err != nil {
+		return nil, err
+	}
+
+	lon, err := meta.seconds(samples, lonIdx, h, spheroid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var latAcc, lonAcc []float32
+
+	if i := meta.sample("latitude_offset_accuracy", -1); i >= 0 && i < len(samples) {
+		if latAcc, err = meta.seconds(samples, i, h, spheroid, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if i := meta.sample("longitude_offset_accuracy", -1); i >= 0 && i < len(samples) {
+		if lonAcc, err = meta.seconds(samples, i, h, spheroid, true); err != nil {
+			return nil, err
+		}
+	}
+
+	sign := float32(-1)
+	if strings.EqualFold(meta.band("positive_value", lonIdx, "east"), "west") {
+		sign = 1
+	}
+
+	grid := newEastGrid(h, lat, lon, sign, latAcc, lonAcc)
+	grid.name = meta.get("grid_name", "")
+
+	return grid, nil
+}
+
+const (
+	tagNewSubfileType     = 254
+	tagImageWidth         = 256
+	tagImageLength        = 257
+	tagBitsPerSample      = 258
+	tagCompression        = 259
+	tagStripOffsets       = 273
+	tagSamplesPerPixel    = 277
+	tagRowsPerStrip       = 278
+	tagStripByteCounts    = 279
+	tagPlanarConfig       = 284
+	tagPredictor          = 317
+	tagTileWidth          = 322
+	tagTileLength         = 323
+	tagTileOffsets        = 324
+	tagTileByteCounts     = 325
+	tagSampleFormat       = 339
+	tagModelPixelScale    = 33550
+	tagModelTiepoint      = 33922
+	tagGeoKeyDirectory    = 34735
+	tagGDALMetadata       = 42112
+	tiffMaxImageDirectory = 1 << 16
+)
+
+type tiff struct {
+	r     io.ReaderAt
+	size  uint64
+	order binary.ByteOrder
+	big   bool
+}
+
+// sizedReaderAt is a file that is read on demand.
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// readerAt returns the reader, if it can be read on demand, e.g. an os.File,
+// or reads it into memory.
+func readerAt(reader io.Reader) (sizedReaderAt, error) {
+	if r, ok := reader.(sizedReaderAt); ok {
+		return r, nil
+	}
+
+	if r, ok := reader.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NewSectionReader(r, 0, size), nil
+	}
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(raw), nil
+}
+
+// newTIFF reads the header of a TIFF or BigTIFF file and returns the offset
+// of the first image directory.
+func newTIFF(r sizedReaderAt) (tiff, uint64, error) {
+	t := tiff{r: r, size: uint64(r.Size())}
+
+	data, err := t.slice(0, min(16, t.size))
+	if err != nil {
+		return tiff{}, 0, err
+	}
+
+	if len(data) < 8 {
+		return tiff{}, 0, errors.New("invalid tiff: too short")
+	}
+
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return tiff{}, 0, fmt.Errorf("invalid tiff byte order %q", data[:2])
+	}
+
+	switch t.order.Uint16(data[2:4]) {
+	case 42:
+		return t, uint64(t.order.Uint32(data[4:8])), nil
+	case 43:
+		if len(data) < 16 {
+			return tiff{}, 0, errors.New("invalid bigtiff: too short")
+		}
+
+		t.big = true
+
+		return t, t.order.Uint64(data[8:16]), nil
+	default:
+		return tiff{}, 0, fmt.Errorf("invalid tiff version %d", t.order.Uint16(data[2:4]))
+	}
+}
+
+// slice reads n bytes at offset.
+func (t tiff) slice(offset, n uint64) ([]byte, error) {
+	if offset > t.size || n > t.size-offset {
+		return nil, fmt.Errorf("invalid tiff: %d bytes at offset %d out of range", n, offset)
+	}
+
+	b := make([]byte, n)
+
+	if _, err := t.r.ReadAt(b, int64(offset)); err != nil && !(errors.Is(err, io.EOF) && offset+n == t.size) {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+type tiffEntry struct {
+	typ   uint16
+	count uint64
+	value []byte
+}
+
+type tiffIFD struct {
+	order   binary.ByteOrder
+	entries map[uint16]tiffEntry
+}
+
+var tiffTypeSize = map[uint16]uint64{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8, 16: 8, 17: 8, 18: 8,
+}
+
+// ifd reads the image directory at offset and returns the offset of the next
+// one.
+func (t tiff) ifd(offset uint64) (tiffIFD, uint64, error) {
+	countSize, entrySize, valueSize := uint64(2), uint64(12), uint64(4)
+	if t.big {
+		countSize, entrySize, valueSize = 8, 20, 8
+	}
+
+	b, err := t.slice(offset, countSize)
+	if err != nil {
+		return tiffIFD{}, 0, err
+	}
+
+	n := uint64(t.order.Uint16(b))
+	if t.big {
+		n = t.order.Uint64(b)
+	}
+
+	if n > tiffMaxImageDirectory {
+		return tiffIFD{}, 0, fmt.Errorf("invalid tiff: %d directory entries", n)
+	}
+
+	entries, err := t.slice(offset+countSize, n*entrySize+valueSize)
+	if err != nil {
+		return tiffIFD{}, 0, err
+	}
+
+	ifd := tiffIFD{order: t.order, entries: make(map[uint16]tiffEntry, n)}
+
+	for i := uint64(0); i < n; i++ {
+		e := entries[i*entrySize : (i+1)*entrySize]
+		tag, typ := t.order.Uint16(e[0:2]), t.order.Uint16(e[2:4])
+
+		var (
+			count uint64
+			field []byte
+		)
+
+		if t.big {
+			count, field = t.order.Uint64(e[4:12]), e[12:20]
+		} else {
+			count, field = uint64(t.order.Uint32(e[4:8])), e[8:12]
+		}
+
+		size, ok := tiffTypeSize[typ]
+		if !ok {
+			continue
+		}
+
+		if count > t.size {
+			return tiffIFD{}, 0, fmt.Errorf("invalid tiff: tag %d with %d values", tag, count)
+		}
+
+		value := field[:min(count*size, valueSize)]
+
+		if count*size > valueSize {
+			off := uint64(t.order.Uint32(field))
+			if t.big {
+				off = t.order.Uint64(field)
+			}
+
+			if value, err = t.slice(off, count*size); err != nil {
+				return tiffIFD{}, 0, err
+			}
+		}
+
+		ifd.entries[tag] = tiffEntry{typ: typ, count: count, value: value}
+	}
+
+	next := entries[n*entrySize:]
+	if t.big {
+		return ifd, t.order.Uint64(next), nil
+	}
+
+	return ifd, uint64(t.order.Uint32(next)), nil
+}
+
+func (ifd tiffIFD) uints(tag uint16) []uint64 {
+	e, ok := ifd.entries[tag]
+	if !ok {
+		return nil
+	}
+
+	values := make([]uint64, e.count)
+
+	for i := range values {
+		switch e.typ {
+		case 1, 6, 7:
+			values[i] = uint64(e.value[i])
+		case 3, 8:
+			values[i] = uint64(ifd.order.Uint16(e.value[i*2:]))
+		case 4, 9:
+			values[i] = uint64(ifd.order.Uint32(e.value[i*4:]))
+		case 16, 17, 18:
+			values[i] = ifd.order.Uint64(e.value[i*8:])
+		default:
+			return nil
+		}
+	}
+
+	return values
+}
+
+// uint returns the first value of a tag or def.
+func (ifd tiffIFD) uint(tag uint16, def uint64) uint64 {
+	if values := ifd.uints(tag); len(values) > 0 {
+		return values[0]
+	}
+
+	return def
+}
+
+func (ifd tiffIFD) floats(tag uint16) []float64 {
+	e, ok := ifd.entries[tag]
+	if !ok {
+		return nil
+	}
+
+	values := make([]float64, e.count)
+
+	for i := range values {
+		switch e.typ {
+		case 11:
+			values[i] = float64(toFloat32(ifd.order, e.value[i*4:]))
+		case 12:
+			values[i] = toFloat(ifd.order, e.value[i*8:])
+		default:
+			return nil
+		}
+	}
+
+	return values
+}
+
+func (ifd tiffIFD) string(tag uint16) string {
+	e, ok := ifd.entries[tag]
+	if !ok || e.typ != 2 {
+		return ""
+	}
+
+	return strings.TrimRight(string(e.value), "\x00")
+}
+
+// geoKey returns the value of a key of the GeoKeyDirectoryTag or 0.
+func geoKey(dir []uint64, key uint64) uint64 {
+	if len(dir) < 4 {
+		return 0
+	}
+
+	for i := 4; i+3 < len(dir); i += 4 {
+		if dir[i] == key && dir[i+1] == 0 {
+			return dir[i+3]
+		}
+	}
+
+	return 0
+}
+
+// raster decodes all samples of an image. The rows of each sample go from
+// north to south.
+func (t tiff) raster(ifd tiffIFD, width, height int) ([][]float32, error) {
+	spp := int(ifd.uint(tagSamplesPerPixel, 1))
+	bps := int(ifd.uint(tagBitsPerSample, 1)) / 8
+	compression := ifd.uint(tagCompression, 1)
+	predictor := ifd.uint(tagPredictor, 1)
+
+	switch {
+	case spp < 1 || spp > 64:
+		return nil, fmt.Errorf("invalid samples per pixel %d", spp)
+	case ifd.uint(tagSampleFormat, 1) != 3 || (bps != 4 && bps != 8):
+		return nil, fmt.Errorf("unsupported sample format %d with %d bits", ifd.uint(tagSampleFormat, 1), bps*8)
+	case compression != 1 && compression != 8 && compression != 32946:
+		return nil, fmt.Errorf("unsupported compression %d", compression)
+	case predictor < 1 || predictor > 3:
+		return nil, fmt.Errorf("unsupported predictor %d", predictor)
+	}
+
+	planes, perPixel := 1, spp
+	if ifd.uint(tagPlanarConfig, 1) == 2 {
+		planes, perPixel = spp, 1
+	}
+
+	chunkWidth, chunkHeight := width, int(min(ifd.uint(tagRowsPerStrip, uint64(height)), uint64(height)))
+	offsets, counts := ifd.uints(tagStripOffsets), ifd.uints(tagStripByteCounts)
+
+	if _, tiled := ifd.entries[tagTileWidth]; tiled {
+		chunkWidth, chunkHeight = int(ifd.uint(tagTileWidth, 0)), int(ifd.uint(tagTileLength, 0))
+		offsets, counts = ifd.uints(tagTileOffsets), ifd.uints(tagTileByteCounts)
+	}
+
+	if chunkWidth < 1 || chunkHeight < 1 || chunkWidth*chunkHeight > 1<<26 {
+		return nil, fmt.Errorf("invalid chunk size %dx%d", chunkWidth, chunkHeight)
+	}
+
+	across, down := (width+chunkWidth-1)/chunkWidth, (height+chunkHeight-1)/chunkHeight
+
+	if len(offsets) < planes*across*down || len(counts) < len(offsets) {
+		return nil, fmt.Errorf("invalid tiff: %d chunks, expected %d", len(offsets), planes*across*down)
+	}
+
+	samples := make([][]float32, spp)
+	for i := range samples {
+		samples[i] = make([]float32, width*height)
+	}
+
+	rowSize := chunkWidth * perPixel * bps
+	chunk := make([]byte, rowSize*chunkHeight)
+
+	for p := 0; p < planes; p++ {
+		for cy := 0; cy < down; cy++ {
+			for cx := 0; cx < across; cx++ {
+				i := p*across*down + cy*across + cx
+
+				raw, err := t.slice(offsets[i], counts[i])
+				if err != nil {
+					return nil, err
+				}
+
+				if err := decompress(chunk, raw, compression); err != nil {
+					return nil, fmt.Errorf("invalid tiff chunk %d: %w", i, err)
+				}
+
+				order := t.order
+
+				for row := 0; row < chunkHeight; row++ {
+					b := chunk[row*rowSize : (row+1)*rowSize]
+
+					switch predictor {
+					case 2:
+						horizontalPredictor(t.order, b, perPixel, bps)
+					case 3:
+						floatPredictor(b, perPixel, bps)
+
+						order = binary.BigEndian
+					}
+				}
+
+				for y := 0; y < chunkHeight && cy*chunkHeight+y < height; y++ {
+					for x := 0; x < chunkWidth && cx*chunkWidth+x < width; x++ {
+						for s := 0; s < perPixel; s++ {
+							k := ((y*chunkWidth+x)*perPixel + s) * bps
+
+							var v float32
+
+							if bps == 4 {
+								v = toFloat32(order, chunk[k:k+4])
+							} else {
+								v = float32(toFloat(order, chunk[k:k+8]))
+							}
+
+							samples[p+s][(cy*chunkHeight+y)*width+cx*chunkWidth+x] = v
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+// decompress fills chunk with the decompressed data. Chunks at the end of the
+// image might be shorter.
+func decompress(chunk, raw []byte, compression uint64) error {
+	clear(chunk)
+
+	if compression == 1 {
+		copy(chunk, raw)
+
+		return nil
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	if _, err := io.ReadFull(r, chunk); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	return nil
+}
+
+// horizontalPredictor undoes the horizontal differencing of a row.
+func horizontalPredictor(order binary.ByteOrder, row []byte, stride, bps int) {
+	for i := stride * bps; i+bps <= len(row); i += bps {
+		j := i - stride*bps
+
+		if bps == 4 {
+			order.PutUint32(row[i:], order.Uint32(row[i:])+order.Uint32(row[j:]))
+		} else {
+			order.PutUint64(row[i:], order.Uint64(row[i:])+order.Uint64(row[j:]))
+		}
+	}
+}
+
+// floatPredictor undoes the floating point predictor of a row. The values are
+// big endian afterwards.
+func floatPredictor(row []byte, stride, bps int) {
+	for i := stride; i < len(row); i++ {
+		row[i] += row[i-stride]
+	}
+
+	tmp := append([]byte(nil), row...)
+	n := len(row) / bps
+
+	for i := 0; i < n; i++ {
+		for b := 0; b < bps; b++ {
+			row[i*bps+b] = tmp[b*n+i]
+		}
+	}
+}
+
+// gdalMetadata is the content of the GDAL_METADATA tag.
+type gdalMetadata struct {
+	Items []gdalItem `xml:"Item"`
+}
+
+type gdalItem struct {
+	Name   string `xml:"name,attr"`
+	Sample string `xml:"sample,attr"`
+	Role   string `xml:"role,attr"`
+	Value  string `xml:",chardata"`
+}
+
+func parseGDALMetadata(s string) (gdalMetadata, error) {
+	var m gdalMetadata
+
+	if s == "" {
+		return m, nil
+	}
+
+	if err := xml.Unmarshal([]byte(s), &m); err != nil {
+		return m, fmt.Errorf("invalid GDAL_METADATA: %w", err)
+	}
+
+	return m, nil
+}
+
+// inherit adds the items of the first image, except the grid name.
+func (m gdalMetadata) inherit(first gdalMetadata) gdalMetadata {
+	items := append([]gdalItem(nil), m.Items...)
+
+	for _, item := range first.Items {
+		if item.Name == "grid_name" {
+			continue
+		}
+
+		found := false
+
+		for _, own := range m.Items {
+			if own.Name == item.Name && own.Sample == item.Sample && own.Role == item.Role {
+				found = true
+			}
+		}
+
+		if !found {
+			items = append(items, item)
+		}
+	}
+
+	return gdalMetadata{Items: items}
+}
+
+// get returns an item of the dataset.
+func (m gdalMetadata) get(name, def string) string {
+	for _, item := range m.Items {
+		if item.Sample == "" && item.Name == name {
+			return strings.TrimSpace(item.Value)
+		}
+	}
+
+	return def
+}
+
+// band returns an item of a sample by its name or role.
+func (m gdalMetadata) band(name string, sample int, def string) string {
+	for _, item := range m.Items {
+		if item.Sample == strconv.Itoa(sample) && (strings.EqualFold(item.Name, name) || strings.EqualFold(item.Role, name)) {
+			return strings.TrimSpace(item.Value)
+		}
+	}
+
+	return def
+}
+
+// sample returns the index of the sample with the description.
+func (m gdalMetadata) sample(description string, def int) int {
+	for _, item := range m.Items {
+		if strings.EqualFold(item.Role, "description") && strings.TrimSpace(item.Value) == description {
+			if i, err := strconv.Atoi(item.Sample); err == nil {
+				return i
+			}
+		}
+	}
+
+	return def
+}
+
+// seconds returns the values of a sample in seconds ordered from south to
+// north. Values in meters are converted with the radii of the spheroid.
+func (m gdalMetadata) seconds(samples [][]float32, i int, h eastHeader, spheroid Spheroid, lon bool) ([]float32, error) {
+	scale, offset := 1.0, 0.0
+
+	if v, err := strconv.ParseFloat(m.band("scale", i, "1"), 64); err == nil {
+		scale = v
+	}
+
+	if v, err := strconv.ParseFloat(m.band("offset", i, "0"), 64); err == nil {
+		offset = v
+	}
+
+	unit := m.band("unittype", i, "arc-second")
+
+	values := make([]float32, len(samples[i]))
+
+	for r := 0; r < h.rows; r++ {
+		lat := h.south + float64(h.rows-1-r)*h.latInc
+		factor := 1.0
+
+		switch unit {
+		case "arc-second", "arc-seconds":
+		case "degree", "degrees":
+			factor = 3600
+		case "radian", "radians":
+			factor = degree(3600)
+		case "metre", "meter", "metres", "meters":
+			m, n := spheroid.radii(lat)
+
+			if lon {
+				m = n * math.Cos(radian(lat))
+			}
+
+			factor = degree(3600) / m
+		default:
+			return nil, fmt.Errorf("unsupported unit %q of sample %d", unit, i)
+		}
+
+		for c := 0; c < h.cols; c++ {
+			v := float64(samples[i][r*h.cols+c])*scale + offset
+			values[(h.rows-1-r)*h.cols+c] = float32(v * factor)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,59 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestGeoTIFF(t *testing.T) {
+	t.Parallel()
+
+	clarke := NewSpheroid(6378206.4, 294.9786982)
+
+	// geotiff.tif is a striped TIFF with nodes from 39.5N 105W in steps of
+	// 0.5°, a latitude offset of the column in seconds and a longitude offset
+	// of -10" per row from the south. geotiff_bigtiff.tif is a tiled and
+	// deflate compressed BigTIFF with a subgrid of 100" latitude offset
+	// between 38.5N 104.5W and 39N 104W.
+	tests := []struct {
+		file       string
+		lon, lat   float64
+		slon, slat float64
+	}{
+		{"geotiff.tif", -104, 38.5, -10, 2},
+		{"geotiff.tif", -105, 38, 0, 0},
+		{"geotiff.tif", -103.75, 38.25, -5, 2.5},
+		{"geotiff_bigtiff.tif", -104.25, 38.75, 0, 100},
+	}
+
+	for _, tt := range tests {
+		raw, err := os.ReadFile("testdata/" + tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := GeoTIFFFile("testdata/"+tt.file, clarke, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// io.MultiReader hides io.ReaderAt, so that the file is read into memory.
+		reader, err := GeoTIFF(io.MultiReader(bytes.NewReader(raw)), clarke, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, crs := range []CRS{file, reader} {
+			lon, lat, _ := crs.ToBase(tt.lon, tt.lat, 0)
+
+			if math.Abs((lon-tt.lon)*3600-tt.slon) > 1e-6 || math.Abs((lat-tt.lat)*3600-tt.slat) > 1e-6 {
+				t.Errorf("%s %f, %f: shift %f\", %f\", want %f\", %f\"", tt.file, tt.lon, tt.lat,
+					(lon-tt.lon)*3600, (lat-tt.lat)*3600, tt.slon, tt.slat)
+			}
+		}
+	}
+}
@@ -0,0 +1,440 @@
+//nolint:varnamelen,nonamedreturns,ireturn,gochecknoglobals
+package wgs84
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// GridPathEnv is the environment variable of DefaultGridResolver. It contains
+// a list of directories separated by os.PathListSeparator.
+const GridPathEnv = "WGS84_GRID_PATH"
+
+// DefaultGridResolver is used by EPSG. It searches the directories of
+// GridPathEnv, then the grids embedded in this package (unless built with the
+// tag wgs84_noembed) and then the file systems added by AddGridFS.
+var DefaultGridResolver = &GridResolver{
+	env: GridPathEnv,
+	fss: embeddedFS(),
+}
+
+func embeddedFS() []fs.FS {
+	if e := embedded(); e != nil {
+		return []fs.FS{e}
+	}
+
+	return nil
+}
+
+// AddGridFS adds a file system to DefaultGridResolver.
+func AddGridFS(fsys fs.FS) {
+	DefaultGridResolver.Add(fsys)
+}
+
+// GridResolver searches a list of file systems for grids. Loaded grids are
+// kept, but missing grids are searched again once a file system is added or
+// the environment variable changes.
+type GridResolver struct {
+	mu  sync.RWMutex
+	env string
+	fss []fs.FS
+	gen atomic.Uint64
+}
+
+func NewGridResolver(fss ...fs.FS) *GridResolver {
+	return &GridResolver{fss: fss}
+}
+
+func (r *GridResolver) Add(fsys fs.FS) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fss = append(r.fss, fsys)
+	r.gen.Add(1)
+}
+
+// gridSource identifies the sources of a resolver. It changes if a file system
+// is added or the environment variable is changed.
+type gridSource struct {
+	env string
+	gen uint64
+}
+
+func (r *GridResolver) source() gridSource {
+	s := gridSource{gen: r.gen.Load()}
+
+	if r.env != "" {
+		s.env = os.Getenv(r.env)
+	}
+
+	return s
+}
+
+func (r *GridResolver) sources() []fs.FS {
+	var fss []fs.FS
+
+	if r.env != "" {
+		for _, dir := range filepath.SplitList(os.Getenv(r.env)) {
+			if dir != "" {
+				fss = append(fss, os.DirFS(dir))
+			}
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append(fss, r.fss...)
+}
+
+// Open opens the first grid with the name.
+func (r *GridResolver) Open(name string) (fs.File, error) {
+	fss := r.sources()
+
+	for _, fsys := range fss {
+		if file, err := fsys.Open(name); err == nil {
+			return file, nil
+		}
+	}
+
+	if r.env != "" {
+		return nil, fmt.Errorf("%w: %s not found in %d file systems (set %s to add directories)", ErrGridMissing, name, len(fss), r.env)
+	}
+
+	return nil, fmt.Errorf("%w: %s not found in %d file systems", ErrGridMissing, name, len(fss))
+}
+
+// NTv2 returns a NTv2 grid, which is loaded on its first use. See NTv2.
+func (r *GridResolver) NTv2(name string, spheroid Spheroid, base CRS) CRS {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return lazyGrid{&lazyGridState{
+		format:   "NTv2",
+		name:     strings.TrimSuffix(name, filepath.Ext(name)),
+		spheroid: spheroid,
+		base:     base,
+		resolver: r,
+		open: func() (ntv2, error) {
+			return r.loadNTv2(name, spheroid, base)
+		},
+	}}
+}
+
+// CTable2 returns a CTable2 grid, which is loaded on its first use. See
+// CTable2.
+func (r *GridResolver) CTable2(name string, spheroid Spheroid, base CRS) CRS {
+	return r.lazy("CTable2", name, spheroid, base, readCTable2)
+}
+
+// NTv1 returns a NTv1 grid, which is loaded on its first use. See NTv1.
+func (r *GridResolver) NTv1(name string, spheroid Spheroid, base CRS) CRS {
+	return r.lazy("NTv1", name, spheroid, base, readNTv1)
+}
+
+// GeoTIFF returns a horizontal offset grid in the GeoTIFF format of PROJ,
+// which is loaded on its first use. See GeoTIFF.
+func (r *GridResolver) GeoTIFF(name string, spheroid Spheroid, base CRS) CRS {
+	return r.lazy("GeoTIFF", name, spheroid, base, readGeoTIFF)
+}
+
+func (r *GridResolver) lazy(format, name string, spheroid Spheroid, base CRS, read func(io.Reader, Spheroid, CRS) (ntv2, error)) CRS {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return lazyGrid{&lazyGridState{
+		format:   format,
+		name:     strings.TrimSuffix(name, filepath.Ext(name)),
+		spheroid: spheroid,
+		base:     base,
+		resolver: r,
+		open: func() (ntv2, error) {
+			file, err := r.Open(name)
+			if err != nil {
+				return ntv2{}, err
+			}
+
+			defer file.Close()
+
+			data, err := read(file, spheroid, base)
+			if err != nil {
+				return ntv2{}, fmt.Errorf("%s: %w", name, err)
+			}
+
+			data.name = strings.TrimSuffix(name, filepath.Ext(name))
+
+			return data, nil
+		},
+	}}
+}
+
+// NADCON returns a NADCON grid pair (name.las and name.los), which is loaded
+// on its first use. See NADCON.
+func (r *GridResolver) NADCON(name string, spheroid Spheroid, base CRS) CRS {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return lazyGrid{&lazyGridState{
+		format:   "NADCON",
+		name:     name,
+		spheroid: spheroid,
+		base:     base,
+		resolver: r,
+		open: func() (ntv2, error) {
+			return r.loadPair(name+".las", name+".los", func(las, los io.Reader) (ntv2, error) {
+				return readNADCON(las, los, spheroid, base)
+			})
+		},
+	}}
+}
+
+// NADCON5 returns a NADCON5 grid pair, which is loaded on its first use. See
+// NADCON5.
+func (r *GridResolver) NADCON5(lat, lon string, spheroid Spheroid, base CRS) CRS {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return lazyGrid{&lazyGridState{
+		format:   "NADCON5",
+		name:     strings.TrimSuffix(lat, filepath.Ext(lat)),
+		spheroid: spheroid,
+		base:     base,
+		resolver: r,
+		open: func() (ntv2, error) {
+			return r.loadPair(lat, lon, func(lat, lon io.Reader) (ntv2, error) {
+				return readNADCON5(lat, lon, spheroid, base)
+			})
+		},
+	}}
+}
+
+func (r *GridResolver) loadPair(first, second string, read func(io.Reader, io.Reader) (ntv2, error)) (ntv2, error) {
+	a, err := r.Open(first)
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	defer a.Close()
+
+	b, err := r.Open(second)
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	defer b.Close()
+
+	data, err := read(a, b)
+	if err != nil {
+		return ntv2{}, fmt.Errorf("%s: %w", first, err)
+	}
+
+	data.name = strings.TrimSuffix(first, filepath.Ext(first))
+
+	return data, nil
+}
+
+// largeGrid is the file size from which grids are read on demand, if the
+// file implements io.ReaderAt. These files stay open.
+const largeGrid = 64 << 20
+
+func (r *GridResolver) loadNTv2(name string, spheroid Spheroid, base CRS) (ntv2, error) {
+	file, err := r.Open(name)
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	var data ntv2
+
+	info, err := file.Stat()
+	if at, ok := file.(io.ReaderAt); ok && err == nil && info.Size() > largeGrid {
+		data, err = decodeNTv2(io.NewSectionReader(at, 0, info.Size()), at, spheroid, base)
+		if err != nil {
+			file.Close()
+		}
+	} else {
+		data, err = readNTv2(file, spheroid, base)
+		file.Close()
+	}
+
+	if err != nil {
+		return ntv2{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	data.name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	return data, nil
+}
+
+// lazyGrid is a grid that is loaded on its first use.
+type lazyGrid struct {
+	*lazyGridState
+}
+
+type lazyGridState struct {
+	format        string
+	name          string
+	spheroid      Spheroid
+	base          CRS
+	resolver      *GridResolver
+	open          func() (ntv2, error)
+	policy        OutOfGrid
+	fallback      CRS
+	interpolation Interpolation
+	tolerance     float64
+	cache         gridCache
+}
+
+// load returns the grid or an errorCRS. If the grid is missing and a fallback
+// is set, all points are transformed with the fallback.
+func (l lazyGrid) load() CRS {
+	return l.cache.load(l.resolver, func() (CRS, bool) {
+		data, err := l.open()
+
+		switch {
+		case err == nil:
+			return WithOutOfGrid(WithTolerance(WithInterpolation(data, l.interpolation), l.tolerance), l.policy, l.fallback), true
+		case l.policy == OutOfGridFallback:
+			return WithOutOfGrid(ntv2{
+				format:   l.format,
+				name:     l.name,
+				spheroid: l.spheroid,
+				base:     l.base,
+			}, l.policy, l.fallback), false
+		default:
+			return errorCRS{err: err}, false
+		}
+	})
+}
+
+// gridCache holds a loaded grid. Failed loads are only kept until the sources
+// of the resolver change, so that grids added later are found.
+type gridCache struct {
+	mu    sync.Mutex
+	state atomic.Pointer[gridCacheState]
+}
+
+type gridCacheState struct {
+	crs    CRS
+	ok     bool
+	source gridSource
+}
+
+func (c *gridCache) load(r *GridResolver, open func() (CRS, bool)) CRS {
+	if s := c.state.Load(); s != nil && s.ok {
+		return s.crs
+	}
+
+	source := r.source()
+
+	if s := c.state.Load(); s != nil && s.source == source {
+		return s.crs
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s := c.state.Load(); s != nil && (s.ok || s.source == source) {
+		return s.crs
+	}
+
+	crs, ok := open()
+	c.state.Store(&gridCacheState{crs: crs, ok: ok, source: source})
+
+	return crs
+}
+
+// clone returns a copy that is not loaded yet.
+func (l lazyGrid) clone() *lazyGridState {
+	return &lazyGridState{
+		format:        l.format,
+		name:          l.name,
+		spheroid:      l.spheroid,
+		base:          l.base,
+		resolver:      l.resolver,
+		open:          l.open,
+		policy:        l.policy,
+		fallback:      l.fallback,
+		interpolation: l.interpolation,
+		tolerance:     l.tolerance,
+	}
+}
+
+func (l lazyGrid) withOutOfGrid(policy OutOfGrid, fallback CRS) CRS {
+	c := l.clone()
+	c.policy, c.fallback = policy, fallback
+
+	return lazyGrid{c}
+}
+
+func (l lazyGrid) withInterpolation(method Interpolation) CRS {
+	c := l.clone()
+	c.interpolation = method
+
+	return lazyGrid{c}
+}
+
+func (l lazyGrid) withTolerance(tolerance float64) CRS {
+	c := l.clone()
+	c.tolerance = tolerance
+
+	return lazyGrid{c}
+}
+
+func (l lazyGrid) String() string {
+	return fmt.Sprint(l.load())
+}
+
+func (l lazyGrid) describe(fromBase bool) string {
+	if d, ok := l.load().(describer); ok {
+		return d.describe(fromBase)
+	}
+
+	return inverse(l.format+" "+l.name, fromBase)
+}
+
+func (l lazyGrid) Base() CRS {
+	return l.base
+}
+
+func (l lazyGrid) Spheroid() Spheroid {
+	return l.spheroid
+}
+
+func (l lazyGrid) ToBase(lon, lat, h float64) (float64, float64, float64) {
+	return l.load().ToBase(lon, lat, h)
+}
+
+func (l lazyGrid) FromBase(lon, lat, h float64) (float64, float64, float64) {
+	return l.load().FromBase(lon, lat, h)
+}
+
+func (l lazyGrid) SafeToBase(lon, lat, h float64) (float64, float64, float64, error) {
+	return Step{CRS: l.load()}.call(lon, lat, h)
+}
+
+func (l lazyGrid) SafeFromBase(lon, lat, h float64) (float64, float64, float64, error) {
+	return Step{CRS: l.load(), FromBase: true}.call(lon, lat, h)
+}
+
+func (l lazyGrid) report(fromBase bool, lon, lat, h float64) (float64, float64, float64, Status, error) {
+	return Step{CRS: l.load(), FromBase: fromBase}.report(lon, lat, h)
+}
+
+func (l lazyGrid) Accuracy(lon, lat float64) Accuracy {
+	if g, ok := l.load().(gridCRS); ok {
+		return g.Accuracy(lon, lat)
+	}
+
+	return Accuracy{Lon: math.NaN(), Lat: math.NaN()}
+}
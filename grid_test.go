@@ -0,0 +1,37 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestLazyGridSources(t *testing.T) {
+	clarke := NewSpheroid(6378206.4, 294.9786982)
+
+	tests := []struct {
+		name string
+		add  func(r *GridResolver)
+	}{
+		{"AddGridFS", func(r *GridResolver) { r.Add(os.DirFS("testdata")) }},
+		{"env", func(_ *GridResolver) { t.Setenv("WGS84_TEST_GRID_PATH", "testdata") }},
+	}
+
+	for _, tt := range tests {
+		resolver := &GridResolver{env: "WGS84_TEST_GRID_PATH"}
+		step := Step{CRS: resolver.NTv1("ntv1.dat", clarke, nil)}
+
+		if _, _, _, err := step.call(-75.1, 45.1, 0); !errors.Is(err, ErrGridMissing) {
+			t.Fatalf("%s: error %v, want ErrGridMissing", tt.name, err)
+		}
+
+		tt.add(resolver)
+
+		if _, _, _, err := step.call(-75.1, 45.1, 0); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+
+		t.Setenv("WGS84_TEST_GRID_PATH", "")
+	}
+}
@@ -0,0 +1,189 @@
+//nolint:varnamelen,gomnd
+package wgs84
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CTable2File loads a grid in the CTable2 format of PROJ from the file system.
+// See CTable2.
+func CTable2File(path string, spheroid Spheroid, base CRS) (CRS, error) {
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readCTable2(r[0], spheroid, base)
+	}, path)
+}
+
+// CTable2 reads a grid in the CTable2 format of PROJ. The grid shifts
+// coordinates on the spheroid to the base CRS, which defaults to EPSG 4326.
+func CTable2(reader io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readCTable2(reader, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// readCTable2 reads the 160 byte header and the shifts as pairs of float32 in
+// radians from west to east. Longitude shifts are positive west.
+func readCTable2(reader io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	head := make([]byte, 160)
+
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return ntv2{}, err
+	}
+
+	if magic := toString(head[:16]); !strings.HasPrefix(magic, "CTABLE V2") {
+		return ntv2{}, fmt.Errorf("invalid ctable2 magic %q", magic)
+	}
+
+	order := binary.LittleEndian
+
+	h := eastHeader{
+		west:   degree(toFloat(order, head[96:104])),
+		south:  degree(toFloat(order, head[104:112])),
+		lonInc: degree(toFloat(order, head[112:120])),
+		latInc: degree(toFloat(order, head[120:128])),
+		cols:   int(int32(order.Uint32(head[128:132]))),
+		rows:   int(int32(order.Uint32(head[132:136]))),
+	}
+
+	if h.cols < 2 || h.rows < 2 || h.cols > 1<<20 || h.rows > 1<<20 || h.lonInc <= 0 || h.latInc <= 0 {
+		return ntv2{}, fmt.Errorf("invalid ctable2 header: %d columns, %d rows", h.cols, h.rows)
+	}
+
+	lat, lon := make([]float32, h.cols*h.rows), make([]float32, h.cols*h.rows)
+	row := make([]byte, 8*h.cols)
+	sec := float32(180 * 3600 / math.Pi)
+
+	for r := 0; r < h.rows; r++ {
+		if _, err := io.ReadFull(reader, row); err != nil {
+			return ntv2{}, err
+		}
+
+		for c := 0; c < h.cols; c++ {
+			lon[r*h.cols+c] = toFloat32(order, row[c*8:]) * sec
+			lat[r*h.cols+c] = toFloat32(order, row[c*8+4:]) * sec
+		}
+	}
+
+	grid := newEastGrid(h, lat, lon, 1)
+	grid.name = toString(head[16:96])
+
+	return newGridShift("CTable2", grid, spheroid, base), nil
+}
+
+// NTv1File loads a NTv1 grid from the file system. See NTv1.
+func NTv1File(path string, spheroid Spheroid, base CRS) (CRS, error) {
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readNTv1(r[0], spheroid, base)
+	}, path)
+}
+
+// NTv1 reads a NTv1 grid. The grid shifts coordinates on the spheroid to the
+// base CRS, which defaults to EPSG 4326.
+func NTv1(reader io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readNTv1(reader, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// readNTv1 reads the header of 12 records and the shifts as big endian pairs of
+// float64 in seconds in the order of a NTv2 subgrid.
+func readNTv1(reader io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	head := make([]byte, 192)
+
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return ntv2{}, err
+	}
+
+	if key := toString(head[:8]); key != "HEADER" {
+		return ntv2{}, fmt.Errorf("invalid ntv1 header: %q", key)
+	}
+
+	order := binary.BigEndian
+
+	if n := order.Uint32(head[8:12]); n != 12 {
+		return ntv2{}, fmt.Errorf("invalid ntv1 header: %d records", n)
+	}
+
+	grid := &ntv2Grid{
+		sLat:    toFloat(order, head[24:32]) * 3600,
+		nLat:    toFloat(order, head[40:48]) * 3600,
+		eLong:   toFloat(order, head[56:64]) * 3600,
+		wLong:   toFloat(order, head[72:80]) * 3600,
+		latInc:  toFloat(order, head[88:96]) * 3600,
+		longInc: toFloat(order, head[104:112]) * 3600,
+	}
+
+	if !(grid.latInc > 0 && grid.longInc > 0 && grid.nLat >= grid.sLat && grid.wLong >= grid.eLong) {
+		return ntv2{}, fmt.Errorf("invalid ntv1 header: N GRID %f, W GRID %f", grid.latInc/3600, grid.longInc/3600)
+	}
+
+	cols, rows := grid.cols(), grid.rows()
+	if cols*rows > 1<<28 {
+		return ntv2{}, fmt.Errorf("invalid ntv1 header: %d columns, %d rows", cols, rows)
+	}
+
+	records := make(memRecords, cols*rows)
+	set := make([]byte, 16)
+
+	for i := range records {
+		if _, err := io.ReadFull(reader, set); err != nil {
+			return ntv2{}, err
+		}
+
+		records[i] = [4]float32{float32(toFloat(order, set[0:8])), float32(toFloat(order, set[8:16])), -1, -1}
+	}
+
+	grid.records = records
+	grid.gsCount = int32(len(records))
+
+	return newGridShift("NTv1", grid, spheroid, base), nil
+}
+
+// gridFile reads a grid from one or more files of the file system. The grid
+// is named after the first file.
+func gridFile(read func(...io.Reader) (ntv2, error), paths ...string) (CRS, error) {
+	readers := make([]io.Reader, len(paths))
+
+	for i, path := range paths {
+		file, err := openGrid(path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer file.Close()
+
+		readers[i] = file
+	}
+
+	data, err := read(readers...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", paths[0], err)
+	}
+
+	data.name = strings.TrimSuffix(filepath.Base(paths[0]), filepath.Ext(paths[0]))
+
+	return data, nil
+}
+
+// openGrid opens a grid file of the file system. Missing files are reported as
+// ErrGridMissing.
+func openGrid(path string) (*os.File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGridMissing, err)
+	}
+
+	return file, nil
+}
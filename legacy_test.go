@@ -0,0 +1,99 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNTv1File(t *testing.T) {
+	t.Parallel()
+
+	crs, err := NTv1File("testdata/ntv1.dat", NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testdata/ntv1.dat has the header records of ntv1_can.dat with a grid of
+	// 3x3 nodes between 45N 75W and 45.5N 75.5W. The shifts are linear with
+	// 0.3" + 0.4"/° of latitude and -1.5" + 0.8"/° of longitude (positive west).
+	tests := []struct {
+		lon, lat   float64
+		slon, slat float64
+	}{
+		{-75, 45, 1.5, 0.3},
+		{-75.5, 45.5, 1.1, 0.5},
+		{-75.125, 45.125, 1.4, 0.35},
+		{-75.3, 45.4, 1.26, 0.46},
+	}
+
+	for _, tt := range tests {
+		lon, lat, _ := crs.ToBase(tt.lon, tt.lat, 0)
+
+		if math.Abs((lon-tt.lon)*3600-tt.slon) > 1e-6 || math.Abs((lat-tt.lat)*3600-tt.slat) > 1e-6 {
+			t.Errorf("%f, %f: shift %f\", %f\", want %f\", %f\"", tt.lon, tt.lat,
+				(lon-tt.lon)*3600, (lat-tt.lat)*3600, tt.slon, tt.slat)
+		}
+	}
+}
+
+func TestGridFileMissing(t *testing.T) {
+	t.Parallel()
+
+	clarke := NewSpheroid(6378206.4, 294.9786982)
+
+	tests := []struct {
+		name string
+		open func(path string) (CRS, error)
+	}{
+		{"NTv2", func(path string) (CRS, error) { return NTv2File(path, clarke, nil) }},
+		{"NTv1", func(path string) (CRS, error) { return NTv1File(path, clarke, nil) }},
+		{"CTable2", func(path string) (CRS, error) { return CTable2File(path, clarke, nil) }},
+		{"GeoTIFF", func(path string) (CRS, error) { return GeoTIFFFile(path, clarke, nil) }},
+		{"NADCON", func(path string) (CRS, error) { return NADCONFile(path, clarke, nil) }},
+		{"NADCON5", func(path string) (CRS, error) { return NADCON5File(path, path, clarke, nil) }},
+		{"GTX", func(path string) (CRS, error) { return GTXFile(path, nil) }},
+		{"EGM96", func(path string) (CRS, error) { return EGM96File(path, nil) }},
+		{"EGM2008", func(path string) (CRS, error) { return EGM2008File(path, nil) }},
+		{"ISG", func(path string) (CRS, error) { return ISGFile(path, nil) }},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.open("testdata/missing"); !errors.Is(err, ErrGridMissing) {
+			t.Errorf("%s: error %v, want ErrGridMissing", tt.name, err)
+		}
+	}
+}
+
+func TestCTable2File(t *testing.T) {
+	t.Parallel()
+
+	crs, err := CTable2File("testdata/ctable2.ct2", NewSpheroid(6377397.155, 299.1528128), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testdata/ctable2.ct2 has 3x3 nodes between 50N 10E and 50.5N 10.5E.
+	// The shifts are linear with 0.5" - 0.8"/° of latitude + 0.4"/° of
+	// longitude and -1" + 1.2"/° of longitude + 0.4"/° of latitude (positive
+	// east).
+	tests := []struct {
+		lon, lat   float64
+		slon, slat float64
+	}{
+		{10, 50, -1, 0.5},
+		{10.5, 50.5, -0.2, 0.3},
+		{10.125, 50.375, -0.7, 0.25},
+		{10.4, 50.1, -0.48, 0.58},
+	}
+
+	for _, tt := range tests {
+		lon, lat, _ := crs.ToBase(tt.lon, tt.lat, 0)
+
+		if math.Abs((lon-tt.lon)*3600-tt.slon) > 1e-5 || math.Abs((lat-tt.lat)*3600-tt.slat) > 1e-5 {
+			t.Errorf("%f, %f: shift %f\", %f\", want %f\", %f\"", tt.lon, tt.lat,
+				(lon-tt.lon)*3600, (lat-tt.lat)*3600, tt.slon, tt.slat)
+		}
+	}
+}
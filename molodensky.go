@@ -0,0 +1,155 @@
+//nolint:varnamelen,nonamedreturns
+package wgs84
+
+import (
+	"fmt"
+	"math"
+)
+
+// Molodensky returns a geographic CRS on the spheroid (EPSG method 9604). da
+// and df are the differences of the base spheroid minus the spheroid.
+func Molodensky(base CRS, spheroid Spheroid, dx, dy, dz, da, df float64) CRS {
+	return newMolodensky(base, spheroid, dx, dy, dz, da, df, false)
+}
+
+// AbridgedMolodensky is like Molodensky, but uses the abridged formulas (EPSG
+// method 9605).
+func AbridgedMolodensky(base CRS, spheroid Spheroid, dx, dy, dz, da, df float64) CRS {
+	return newMolodensky(base, spheroid, dx, dy, dz, da, df, true)
+}
+
+func newMolodensky(base CRS, spheroid Spheroid, dx, dy, dz, da, df float64, abridged bool) molodensky {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return molodensky{
+		base:     base,
+		s:        spheroid,
+		dx:       dx,
+		dy:       dy,
+		dz:       dz,
+		da:       da,
+		df:       df,
+		abridged: abridged,
+	}
+}
+
+type molodensky struct {
+	base               CRS
+	s                  Spheroid
+	dx, dy, dz, da, df float64
+	abridged           bool
+	tolerance          float64
+}
+
+func (m molodensky) Base() CRS {
+	return m.base
+}
+
+func (m molodensky) Spheroid() Spheroid {
+	return m.s
+}
+
+func (m molodensky) describe(fromBase bool) string {
+	name := "Molodensky"
+	if m.abridged {
+		name = "AbridgedMolodensky"
+	}
+
+	return inverse(fmt.Sprintf("%s(dx=%.10g, dy=%.10g, dz=%.10g, da=%.10g, df=%.10g, %s)", name, m.dx, m.dy, m.dz, m.da, m.df, m.s), fromBase)
+}
+
+// shift returns the differences of longitude and latitude in degrees and of
+// the height in meters at a point on the spheroid.
+func (m molodensky) shift(lon, lat, h float64) (dlon, dlat, dh float64) {
+	phi, lambda := radian(lat), radian(lon)
+	sinPhi, cosPhi := math.Sincos(phi)
+	sinLambda, cosLambda := math.Sincos(lambda)
+	rho, nu := m.s.radii(lat)
+
+	a, f := m.s.A, m.s.F
+
+	if m.abridged {
+		adf := a*m.df + f*m.da
+
+		dlat = (-m.dx*sinPhi*cosLambda - m.dy*sinPhi*sinLambda + m.dz*cosPhi + adf*math.Sin(2*phi)) / rho
+		dlon = (-m.dx*sinLambda + m.dy*cosLambda) / (nu * cosPhi)
+		dh = m.dx*cosPhi*cosLambda + m.dy*cosPhi*sinLambda + m.dz*sinPhi + adf*sinPhi*sinPhi - m.da
+
+		return degree(dlon), degree(dlat), dh
+	}
+
+	b := m.s.B
+
+	dlat = (-m.dx*sinPhi*cosLambda - m.dy*sinPhi*sinLambda + m.dz*cosPhi +
+		m.da*nu*m.s.E2*sinPhi*cosPhi/a + m.df*(rho*a/b+nu*b/a)*sinPhi*cosPhi) / (rho + h)
+	dlon = (-m.dx*sinLambda + m.dy*cosLambda) / ((nu + h) * cosPhi)
+	dh = m.dx*cosPhi*cosLambda + m.dy*cosPhi*sinLambda + m.dz*sinPhi - m.da*a/nu + m.df*b/a*nu*sinPhi*sinPhi
+
+	return degree(dlon), degree(dlat), dh
+}
+
+func (m molodensky) ToBase(lon, lat, h float64) (float64, float64, float64) {
+	dlon, dlat, dh := m.shift(lon, lat, h)
+
+	return lon + dlon, lat + dlat, h + dh
+}
+
+func (m molodensky) FromBase(lon, lat, h float64) (float64, float64, float64) {
+	lon, lat, h, _ = m.fromBase(lon, lat, h)
+
+	return lon, lat, h
+}
+
+func (m molodensky) SafeToBase(lon, lat, h float64) (float64, float64, float64, error) {
+	lon, lat, h = m.ToBase(lon, lat, h)
+
+	return lon, lat, h, nil
+}
+
+func (m molodensky) SafeFromBase(lon, lat, h float64) (float64, float64, float64, error) {
+	return m.fromBase(lon, lat, h)
+}
+
+// fromBase inverts the shifts iteratively.
+func (m molodensky) fromBase(lon, lat, h float64) (qlon, qlat, qh float64, err error) {
+	qlon, qlat, qh = lon, lat, h
+	tol := tolerance(m.tolerance)
+
+	for i := 0; i < maxIterations; i++ {
+		dlon, dlat, dh := m.shift(qlon, qlat, qh)
+
+		nlon, nlat := lon-dlon, lat-dlat
+		converged := math.Abs(nlon-qlon) <= tol && math.Abs(nlat-qlat) <= tol || math.IsNaN(nlon+nlat)
+
+		qlon, qlat, qh = nlon, nlat, h-dh
+
+		if converged {
+			return qlon, qlat, qh, nil
+		}
+	}
+
+	return math.NaN(), math.NaN(), math.NaN(), fmt.Errorf("%w: inverse molodensky at %f, %f", ErrNotConverged, lon, lat)
+}
+
+func (m molodensky) ToBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		xs[i], ys[i], zs[i] = m.ToBase(xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (m molodensky) FromBaseSlice(xs, ys, zs []float64) error {
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = m.fromBase(xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMolodensky(t *testing.T) {
+	t.Parallel()
+
+	// WGS 84 to ED50, EPSG Guidance Note 7-2, 2.4.4.2.
+	wgs, ed50 := NewSpheroid(6378137, 298.257223563), Geographic(nil, NewSpheroid(6378388, 297))
+
+	tests := []struct {
+		name       string
+		crs        CRS
+		wlon, wlat float64
+		wh         float64
+	}{
+		{"9604", Molodensky(ed50, wgs, 84.87, 96.49, 116.95, 251, 1.41927e-5), 51.477, 36.565, 28.02},
+		{"9605", AbridgedMolodensky(ed50, wgs, 84.87, 96.49, 116.95, 251, 1.41927e-5), 51.477, 36.563, 28.091},
+	}
+
+	lon0, lat0 := 2+7/60.0+46.38/3600, 53+48/60.0+33.82/3600
+
+	for _, tt := range tests {
+		lon, lat, h := tt.crs.ToBase(lon0, lat0, 73)
+
+		if math.Abs((lon-2-7/60.0)*3600-tt.wlon) > 5e-4 || math.Abs((lat-53-48/60.0)*3600-tt.wlat) > 5e-4 || math.Abs(h-tt.wh) > 5e-3 {
+			t.Errorf("%s: 2°07'%.4f\", 53°48'%.4f\", %.3f m, want 2°07'%.3f\", 53°48'%.3f\", %.3f m", tt.name,
+				(lon-2-7/60.0)*3600, (lat-53-48/60.0)*3600, h, tt.wlon, tt.wlat, tt.wh)
+		}
+
+		lon, lat, h, err := Step{CRS: tt.crs, FromBase: true}.call(lon, lat, h)
+		if err != nil || math.Abs(lon-lon0) > DefaultTolerance || math.Abs(lat-lat0) > DefaultTolerance || math.Abs(h-73) > 1e-3 {
+			t.Errorf("%s inverse: %.10f, %.10f, %.4f, %v", tt.name, lon, lat, h, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+//nolint:varnamelen,gomnd
+package wgs84
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// NADCONFile loads a NADCON grid pair from the file system. path is the path of
+// the .las file or of both files without extension. The grid shifts
+// coordinates on the spheroid to the base CRS, which defaults to EPSG 4326.
+func NADCONFile(path string, spheroid Spheroid, base CRS) (CRS, error) {
+	path = strings.TrimSuffix(path, filepath.Ext(path))
+
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readNADCON(r[0], r[1], spheroid, base)
+	}, path+".las", path+".los")
+}
+
+// NADCON reads a binary NADCON grid pair of latitude (.las) and longitude
+// (.los) shifts. The grid shifts coordinates on the spheroid to the base CRS,
+// which defaults to EPSG 4326.
+func NADCON(las, los io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readNADCON(las, los, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func readNADCON(las, los io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	lat, err := decodeNADCON(las)
+	if err != nil {
+		return ntv2{}, fmt.Errorf("invalid nadcon .las: %w", err)
+	}
+
+	lon, err := decodeNADCON(los)
+	if err != nil {
+		return ntv2{}, fmt.Errorf("invalid nadcon .los: %w", err)
+	}
+
+	if lat.header != lon.header {
+		return ntv2{}, fmt.Errorf("invalid nadcon grids: %v and %v differ", lat.header, lon.header)
+	}
+
+	// NADCON longitude shifts are already positive west.
+	grid := newEastGrid(lat.header, lat.values, lon.values, 1)
+	grid.name = lat.name
+
+	return newGridShift("NADCON", grid, spheroid, base), nil
+}
+
+// eastHeader describes a grid with values ordered from west to east and from
+// south to north. All values are in degrees and longitudes are positive east.
+type eastHeader struct {
+	west, south, lonInc, latInc float64
+	cols, rows                  int
+}
+
+type eastValues struct {
+	header eastHeader
+	name   string
+	values []float32
+}
+
+// decodeNADCON reads a binary NADCON grid. Each record starts with an int32
+// and is followed by one float32 per column. The first record is the header.
+func decodeNADCON(reader io.Reader) (eastValues, error) {
+	head := make([]byte, 96)
+
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return eastValues{}, err
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+
+	if cols := binary.LittleEndian.Uint32(head[64:68]); cols == 0 || cols > 1<<20 {
+		order = binary.BigEndian
+	}
+
+	cols, rows, nz := int(order.Uint32(head[64:68])), int(order.Uint32(head[68:72])), int(order.Uint32(head[72:76]))
+	if cols < 2 || rows < 2 || cols > 1<<20 || rows > 1<<20 || nz != 1 {
+		return eastValues{}, fmt.Errorf("invalid header: %d columns, %d rows, %d values", cols, rows, nz)
+	}
+
+	data := eastValues{
+		header: eastHeader{
+			west:   float64(toFloat32(order, head[76:80])),
+			lonInc: float64(toFloat32(order, head[80:84])),
+			south:  float64(toFloat32(order, head[84:88])),
+			latInc: float64(toFloat32(order, head[88:92])),
+			cols:   cols,
+			rows:   rows,
+		},
+		name:   toString(head[:56]),
+		values: make([]float32, cols*rows),
+	}
+
+	record := make([]byte, 4*(cols+1))
+
+	// The header record is padded to the length of a record.
+	if len(record) > len(head) {
+		if _, err := io.ReadFull(reader, record[:len(record)-len(head)]); err != nil {
+			return eastValues{}, err
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		if _, err := io.ReadFull(reader, record); err != nil {
+			return eastValues{}, err
+		}
+
+		for c := 0; c < cols; c++ {
+			data.values[r*cols+c] = toFloat32(order, record[4+c*4:])
+		}
+	}
+
+	return data, nil
+}
+
+// NADCON5File loads a NADCON5 grid pair (.b) of latitude and longitude shifts
+// from the file system. See NADCON5.
+func NADCON5File(latPath, lonPath string, spheroid Spheroid, base CRS) (CRS, error) {
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readNADCON5(r[0], r[1], spheroid, base)
+	}, latPath, lonPath)
+}
+
+// NADCON5 reads a NADCON5 grid pair (.b) of latitude (lat.trn) and longitude
+// (lon.trn) shifts in arc seconds. The grid shifts coordinates on the spheroid
+// to the base CRS, which defaults to EPSG 4326.
+func NADCON5(lat, lon io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readNADCON5(lat, lon, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func readNADCON5(latReader, lonReader io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	lat, err := decodeNADCON5(latReader)
+	if err != nil {
+		return ntv2{}, fmt.Errorf("invalid nadcon5 latitude grid: %w", err)
+	}
+
+	lon, err := decodeNADCON5(lonReader)
+	if err != nil {
+		return ntv2{}, fmt.Errorf("invalid nadcon5 longitude grid: %w", err)
+	}
+
+	if lat.header != lon.header {
+		return ntv2{}, fmt.Errorf("invalid nadcon5 grids: %v and %v differ", lat.header, lon.header)
+	}
+
+	// NADCON5 longitude shifts are positive east.
+	return newGridShift("NADCON5", newEastGrid(lat.header, lat.values, lon.values, -1), spheroid, base), nil
+}
+
+// decodeNADCON5 reads a grid in the binary format of NGS (glamn, glomn, dla,
+// dlo as float64, nla, nlo, ikind as int32 and then nla rows of nlo float32
+// values). Files written by Fortran as sequential records are supported, too.
+func decodeNADCON5(reader io.Reader) (eastValues, error) {
+	head := make([]byte, 48)
+
+	if _, err := io.ReadFull(reader, head[:44]); err != nil {
+		return eastValues{}, err
+	}
+
+	var (
+		order  binary.ByteOrder = binary.BigEndian
+		marked bool
+	)
+
+	switch {
+	case binary.BigEndian.Uint32(head) == 44:
+		marked = true
+	case binary.LittleEndian.Uint32(head) == 44:
+		order, marked = binary.LittleEndian, true
+	case !plausibleNADCON5(binary.BigEndian, head):
+		order = binary.LittleEndian
+	}
+
+	if marked {
+		// Skip the leading marker and read the trailing marker.
+		copy(head, head[4:44])
+
+		if _, err := io.ReadFull(reader, head[40:48]); err != nil {
+			return eastValues{}, err
+		}
+	}
+
+	if !plausibleNADCON5(order, head) {
+		return eastValues{}, fmt.Errorf("invalid header")
+	}
+
+	rows, cols := int(order.Uint32(head[32:36])), int(order.Uint32(head[36:40]))
+
+	data := eastValues{
+		header: eastHeader{
+			south:  toFloat(order, head[0:8]),
+			west:   toFloat(order, head[8:16]),
+			latInc: toFloat(order, head[16:24]),
+			lonInc: toFloat(order, head[24:32]),
+			cols:   cols,
+			rows:   rows,
+		},
+		values: make([]float32, cols*rows),
+	}
+
+	if data.header.west > 180 {
+		data.header.west -= 360
+	}
+
+	row := make([]byte, 4*cols)
+
+	for r := 0; r < rows; r++ {
+		if marked {
+			if _, err := io.ReadFull(reader, head[:4]); err != nil {
+				return eastValues{}, err
+			}
+		}
+
+		if _, err := io.ReadFull(reader, row); err != nil {
+			return eastValues{}, err
+		}
+
+		if marked {
+			if _, err := io.ReadFull(reader, head[:4]); err != nil {
+				return eastValues{}, err
+			}
+		}
+
+		for c := 0; c < cols; c++ {
+			data.values[r*cols+c] = toFloat32(order, row[c*4:])
+		}
+	}
+
+	return data, nil
+}
+
+func plausibleNADCON5(order binary.ByteOrder, head []byte) bool {
+	rows, cols, kind := order.Uint32(head[32:36]), order.Uint32(head[36:40]), order.Uint32(head[40:44])
+
+	return rows > 1 && cols > 1 && rows < 1<<20 && cols < 1<<20 && kind == 1
+}
+
+// newEastGrid converts shifts in seconds ordered from west to east into a
+// ntv2Grid. sign is -1 if the longitude shifts are positive east. The
+// accuracies are optional.
+func newEastGrid(h eastHeader, lat, lon []float32, sign float32, accuracies ...[]float32) *ntv2Grid {
+	records := make(memRecords, h.cols*h.rows)
+
+	for r := 0; r < h.rows; r++ {
+		for c := 0; c < h.cols; c++ {
+			i := r*h.cols + c
+			records[r*h.cols+h.cols-1-c] = [4]float32{lat[i], sign * lon[i], -1, -1}
+
+			for j, acc := range accuracies {
+				if acc != nil {
+					records[r*h.cols+h.cols-1-c][2+j] = acc[i]
+				}
+			}
+		}
+	}
+
+	return &ntv2Grid{
+		sLat:    h.south * 3600,
+		nLat:    (h.south + float64(h.rows-1)*h.latInc) * 3600,
+		eLong:   -(h.west + float64(h.cols-1)*h.lonInc) * 3600,
+		wLong:   -h.west * 3600,
+		latInc:  h.latInc * 3600,
+		longInc: h.lonInc * 3600,
+		gsCount: int32(len(records)),
+		records: records,
+	}
+}
+
+// newGridShift returns a grid shift of a single grid in another format than
+// NTv2.
+func newGridShift(format string, grid *ntv2Grid, spheroid Spheroid, base CRS) ntv2 {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	return ntv2{
+		format:   format,
+		name:     grid.name,
+		spheroid: spheroid,
+		base:     base,
+		numFile:  1,
+		grids:    []*ntv2Grid{grid},
+	}
+}
@@ -0,0 +1,39 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNADCONFile(t *testing.T) {
+	t.Parallel()
+
+	crs, err := NADCONFile("testdata/nadcon", NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testdata/nadcon.las and .los have 3x3 nodes between 39N 105W and 39.5N
+	// 104.5W. The shifts are linear with -0.2" + 0.6"/° of latitude + 0.2"/° of
+	// longitude and 2" - 0.4"/° of longitude + 0.8"/° of latitude (positive
+	// east), so bilinear interpolation reproduces them exactly.
+	tests := []struct {
+		lon, lat   float64
+		slon, slat float64
+	}{
+		{-105, 39, 2, -0.2},
+		{-104.5, 39.5, 2.2, 0.2},
+		{-104.875, 39.125, 2.05, -0.1},
+		{-104.6, 39.3, 2.08, 0.06},
+	}
+
+	for _, tt := range tests {
+		lon, lat, _ := crs.ToBase(tt.lon, tt.lat, 0)
+
+		if math.Abs((lon-tt.lon)*3600-tt.slon) > 1e-5 || math.Abs((lat-tt.lat)*3600-tt.slat) > 1e-5 {
+			t.Errorf("%f, %f: shift %f\", %f\", want %f\", %f\"", tt.lon, tt.lat,
+				(lon-tt.lon)*3600, (lat-tt.lat)*3600, tt.slon, tt.slat)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build wgs84_noembed
+
+package wgs84
+
+import "io/fs"
+
+func embedded() fs.FS {
+	return nil
+}
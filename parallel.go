@@ -0,0 +1,89 @@
+//nolint:varnamelen,gomnd
+package wgs84
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const parallelChunk = 4096
+
+// TransformParallel transforms the coordinates in place in chunks on GOMAXPROCS
+// goroutines. zs and progress may be nil. On the first error or cancellation
+// the remaining chunks are skipped, so the slices are partially transformed.
+func TransformParallel(ctx context.Context, t Transformer, xs, ys, zs []float64, progress func(done, total int)) error {
+	if len(ys) != len(xs) || (zs != nil && len(zs) != len(xs)) {
+		return fmt.Errorf("slices of different length: %d, %d, %d", len(xs), len(ys), len(zs))
+	}
+
+	total := len(xs)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     atomic.Int64
+		done     int
+		firstErr error
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+	)
+
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				start := int(next.Add(parallelChunk)) - parallelChunk
+				if start >= total {
+					return
+				}
+
+				end := min(start+parallelChunk, total)
+
+				var z []float64
+
+				if zs != nil {
+					z = zs[start:end]
+				}
+
+				if err := t.Slice(xs[start:end], ys[start:end], z); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+
+					cancel()
+
+					return
+				}
+
+				mu.Lock()
+				done += end - start
+
+				if progress != nil {
+					progress(done, total)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if done < total {
+		return ctx.Err()
+	}
+
+	return nil
+}
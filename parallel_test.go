@@ -0,0 +1,145 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func parallelCoords(n int) ([]float64, []float64, []float64) {
+	xs, ys, zs := make([]float64, n), make([]float64, n), make([]float64, n)
+
+	for i := range xs {
+		xs[i], ys[i], zs[i] = 6+6*float64(i)/float64(n), 47+8*float64(i)/float64(n), float64(i)
+	}
+
+	return xs, ys, zs
+}
+
+func TestTransformParallel(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewTransformer(EPSG(4258), EPSG(25832))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 3*parallelChunk + 100
+
+	xs, ys, zs := parallelCoords(n)
+	wxs, wys, wzs := parallelCoords(n)
+
+	if err := tr.Slice(wxs, wys, wzs); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls, last int
+
+	err = TransformParallel(context.Background(), tr, xs, ys, zs, func(done, total int) {
+		if done <= last || total != n {
+			t.Errorf("progress %d of %d after %d", done, total, last)
+		}
+
+		calls++
+		last = done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 4 || last != n {
+		t.Errorf("%d progress calls up to %d, want 4 up to %d", calls, last, n)
+	}
+
+	for i := range xs {
+		if xs[i] != wxs[i] || ys[i] != wys[i] || zs[i] != wzs[i] {
+			t.Fatalf("%d: %f, %f, %f, want %f, %f, %f", i, xs[i], ys[i], zs[i], wxs[i], wys[i], wzs[i])
+		}
+	}
+}
+
+func TestTransformParallelErrors(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewTransformer(EPSG(4258), Strict(EPSG(25832)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 3*parallelChunk + 100
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		modify func(xs, ys []float64)
+		err    error
+	}{
+		{"cancelled", cancelled, func(_, _ []float64) {}, context.Canceled},
+		{"outside", context.Background(), func(xs, _ []float64) { xs[2*parallelChunk+1] = 50 }, ErrOutsideDomain},
+	}
+
+	for _, tt := range tests {
+		xs, ys, zs := parallelCoords(n)
+		tt.modify(xs, ys)
+
+		ixs, iys, _ := parallelCoords(n)
+		tt.modify(ixs, iys)
+
+		if err := TransformParallel(tt.ctx, tr, xs, ys, zs, nil); !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error %v, want %v", tt.name, err, tt.err)
+		}
+
+		// Each chunk is either transformed or unchanged.
+		for start := 0; start < n; start += parallelChunk {
+			end := min(start+parallelChunk, n)
+			unchanged := xs[start] == ixs[start]
+
+			for i := start; i < end; i++ {
+				if (xs[i] == ixs[i] && ys[i] == iys[i]) != unchanged {
+					t.Fatalf("%s: chunk at %d is partly transformed", tt.name, start)
+				}
+			}
+
+			if tt.err == ErrOutsideDomain && start == 2*parallelChunk && !unchanged {
+				t.Errorf("%s: failed chunk changed", tt.name)
+			}
+
+			if tt.err == context.Canceled && !unchanged {
+				t.Errorf("%s: chunk at %d changed", tt.name, start)
+			}
+		}
+	}
+}
+
+func TestTransformParallelCancel(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewTransformer(EPSG(4258), EPSG(25832))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each goroutine finishes at most one chunk after the cancellation.
+	n := (2*runtime.GOMAXPROCS(0) + 1) * parallelChunk
+	xs, ys, zs := parallelCoords(n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var last int
+
+	err = TransformParallel(ctx, tr, xs, ys, zs, func(done, _ int) {
+		last = done
+
+		cancel()
+	})
+
+	if !errors.Is(err, context.Canceled) || last >= n {
+		t.Errorf("error %v after %d of %d, want context.Canceled", err, last, n)
+	}
+}
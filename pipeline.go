@@ -0,0 +1,505 @@
+//nolint:varnamelen,nonamedreturns,ireturn,gomnd
+package wgs84
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Pipeline is the ordered list of steps that transforms coordinates from one
+// CRS to another.
+type Pipeline struct {
+	steps []Step
+}
+
+// NewPipeline returns the steps from one CRS to another. Both Base chains are
+// only followed up to their lowest common ancestor.
+func NewPipeline(from, to CRS) Pipeline {
+	var fromChain, toChain []CRS
+
+	for ; from != nil; from = from.Base() {
+		fromChain = append(fromChain, from)
+	}
+
+	for ; to != nil; to = to.Base() {
+		toChain = append(toChain, to)
+	}
+
+	i, j := commonAncestor(fromChain, toChain)
+
+	steps := make([]Step, 0, i+j)
+
+	for _, crs := range fromChain[:i] {
+		steps = append(steps, Step{CRS: crs})
+	}
+
+	for k := j - 1; k >= 0; k-- {
+		steps = append(steps, Step{CRS: toChain[k], FromBase: true})
+	}
+
+	return Pipeline{steps: steps}
+}
+
+func commonAncestor(fromChain, toChain []CRS) (int, int) {
+	for i, a := range fromChain {
+		for j, b := range toChain {
+			if equalCRS(a, b) {
+				return i, j
+			}
+		}
+	}
+
+	return len(fromChain), len(toChain)
+}
+
+// equalCRS reports whether both CRSs are equal. Uncomparable CRSs, e.g. grids
+// read from a file, are never equal, but grids of a GridResolver are compared
+// by identity. Areas of use are ignored unless they are strict.
+func equalCRS(a, b CRS) bool {
+	if c, ok := a.(bounded); ok && !c.strict {
+		a = c.crs
+	}
+
+	if c, ok := b.(bounded); ok && !c.strict {
+		b = c.crs
+	}
+
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if !reflect.ValueOf(a).Comparable() || !reflect.ValueOf(b).Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+func (p Pipeline) Steps() []Step {
+	return append([]Step(nil), p.steps...)
+}
+
+func (p Pipeline) Inverse() Pipeline {
+	steps := make([]Step, len(p.steps))
+
+	for i, s := range p.steps {
+		steps[len(steps)-1-i] = Step{CRS: s.CRS, FromBase: !s.FromBase}
+	}
+
+	return Pipeline{steps: steps}
+}
+
+func (p Pipeline) Func() Func {
+	funcs := make([]Func, len(p.steps))
+
+	for i, s := range p.steps {
+		funcs[i] = s.fn()
+	}
+
+	return chainFunc(funcs...)
+}
+
+func (p Pipeline) String() string {
+	if len(p.steps) == 0 {
+		return "Identity"
+	}
+
+	descs := make([]string, len(p.steps))
+
+	for i, s := range p.steps {
+		descs[i] = s.String()
+	}
+
+	return strings.Join(descs, " | ")
+}
+
+// Step is either the ToBase or the FromBase method of a CRS.
+type Step struct {
+	CRS      CRS
+	FromBase bool
+}
+
+// describer is implemented by CRSs to describe their ToBase and FromBase
+// methods.
+type describer interface {
+	describe(fromBase bool) string
+}
+
+func (s Step) String() string {
+	if d, ok := s.CRS.(describer); ok {
+		return d.describe(s.FromBase)
+	}
+
+	method := "ToBase"
+	if s.FromBase {
+		method = "FromBase"
+	}
+
+	if str, ok := s.CRS.(fmt.Stringer); ok {
+		return fmt.Sprintf("%s %s", method, str)
+	}
+
+	return fmt.Sprintf("%T.%s", s.CRS, method)
+}
+
+func (s Step) fn() Func {
+	if s.FromBase {
+		return s.CRS.FromBase
+	}
+
+	return s.CRS.ToBase
+}
+
+// Status reports how a point has been transformed. Higher values take
+// precedence when steps are combined.
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusExtrapolated
+	StatusFallback
+	StatusNaN
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusExtrapolated:
+		return "extrapolated"
+	case StatusFallback:
+		return "fallback"
+	case StatusNaN:
+		return "nan"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// reporter is implemented by CRSs that report a Status.
+type reporter interface {
+	report(fromBase bool, a, b, c float64) (float64, float64, float64, Status, error)
+}
+
+func (s Step) report(a, b, c float64) (float64, float64, float64, Status, error) {
+	if r, ok := s.CRS.(reporter); ok {
+		return r.report(s.FromBase, a, b, c)
+	}
+
+	a, b, c, err := s.call(a, b, c)
+
+	return a, b, c, StatusOK, err
+}
+
+func (s Step) call(a, b, c float64) (float64, float64, float64, error) {
+	if safe, ok := s.CRS.(SafeCRS); ok {
+		if s.FromBase {
+			return safe.SafeFromBase(a, b, c)
+		}
+
+		return safe.SafeToBase(a, b, c)
+	}
+
+	if s.FromBase {
+		a, b, c = s.CRS.FromBase(a, b, c)
+	} else {
+		a, b, c = s.CRS.ToBase(a, b, c)
+	}
+
+	return a, b, c, nil
+}
+
+func (s Step) slice(xs, ys, zs []float64) error {
+	if batch, ok := s.CRS.(BatchCRS); ok {
+		if s.FromBase {
+			return batch.FromBaseSlice(xs, ys, zs)
+		}
+
+		return batch.ToBaseSlice(xs, ys, zs)
+	}
+
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = s.call(xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Transformer is the error-aware counterpart of Func.
+type Transformer struct {
+	pipeline Pipeline
+}
+
+func NewTransformer(from, to CRS) (Transformer, error) {
+	if err := chainError(from); err != nil {
+		return Transformer{}, err
+	}
+
+	if err := chainError(to); err != nil {
+		return Transformer{}, err
+	}
+
+	return Transformer{pipeline: NewPipeline(from, to)}, nil
+}
+
+func chainError(crs CRS) error {
+	for ; crs != nil; crs = crs.Base() {
+		c := crs
+
+		if b, ok := c.(bounded); ok {
+			c = b.crs
+		}
+
+		if l, ok := c.(interface{ load() CRS }); ok {
+			c = l.load()
+		}
+
+		if e, ok := c.(errorCRS); ok {
+			return e.err
+		}
+
+		if v, ok := c.(validator); ok {
+			if err := v.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validator is implemented by CRSs that depend on other CRSs than their base
+// CRS.
+type validator interface {
+	validate() error
+}
+
+func (t Transformer) Pipeline() Pipeline {
+	return t.pipeline
+}
+
+func (t Transformer) Inverse() Transformer {
+	return Transformer{pipeline: t.pipeline.Inverse()}
+}
+
+func (t Transformer) Transform(a, b, c float64) (float64, float64, float64, error) {
+	var err error
+
+	for _, s := range t.pipeline.steps {
+		a, b, c, err = s.call(a, b, c)
+		if err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), err
+		}
+	}
+
+	return a, b, c, nil
+}
+
+// TransformEpoch is like Transform, but the coordinates are at the epoch, a
+// decimal year, which is used by time-dependent transformations.
+func (t Transformer) TransformEpoch(a, b, c, epoch float64) (float64, float64, float64, error) {
+	var err error
+
+	for _, s := range t.pipeline.steps {
+		a, b, c, err = Step{CRS: atEpoch(s.CRS, epoch), FromBase: s.FromBase}.call(a, b, c)
+		if err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), err
+		}
+	}
+
+	return a, b, c, nil
+}
+
+// AtEpoch returns a Transformer of coordinates at the epoch, a decimal year,
+// which is used by time-dependent transformations, e.g. for Slice.
+func (t Transformer) AtEpoch(epoch float64) Transformer {
+	steps := make([]Step, len(t.pipeline.steps))
+
+	for i, s := range t.pipeline.steps {
+		steps[i] = Step{CRS: atEpoch(s.CRS, epoch), FromBase: s.FromBase}
+	}
+
+	return Transformer{pipeline: Pipeline{steps: steps}}
+}
+
+// TransformStatus is like Transform, but also reports the Status.
+func (t Transformer) TransformStatus(a, b, c float64) (float64, float64, float64, Status, error) {
+	var (
+		status Status
+		err    error
+	)
+
+	for _, s := range t.pipeline.steps {
+		var st Status
+
+		a, b, c, st, err = s.report(a, b, c)
+		if err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), st, err
+		}
+
+		status = max(status, st)
+	}
+
+	return a, b, c, status, nil
+}
+
+// Accuracy is the accuracy of a point in meters. NaN means unknown.
+type Accuracy struct {
+	Lon, Lat float64
+}
+
+// gridCRS is implemented by grid shifts that know their accuracy.
+type gridCRS interface {
+	Accuracy(lon, lat float64) Accuracy
+}
+
+func asGrid(crs CRS) (gridCRS, bool) {
+	if b, ok := crs.(bounded); ok {
+		crs = b.crs
+	}
+
+	g, ok := crs.(gridCRS)
+
+	return g, ok
+}
+
+// TransformAccuracy is like TransformStatus, but also reports the accuracy of
+// the grid shifts. The accuracies of multiple grids are added in quadrature.
+// The accuracy is unknown if no grid is used.
+func (t Transformer) TransformAccuracy(a, b, c float64) (float64, float64, float64, Accuracy, Status, error) {
+	var (
+		status Status
+		err    error
+		lon    float64
+		lat    float64
+		grids  int
+	)
+
+	for _, s := range t.pipeline.steps {
+		g, isGrid := asGrid(s.CRS)
+
+		var acc Accuracy
+
+		if isGrid && !s.FromBase {
+			acc = g.Accuracy(a, b)
+		}
+
+		var st Status
+
+		a, b, c, st, err = s.report(a, b, c)
+		if err != nil {
+			return math.NaN(), math.NaN(), math.NaN(), Accuracy{Lon: math.NaN(), Lat: math.NaN()}, st, err
+		}
+
+		status = max(status, st)
+
+		if !isGrid {
+			continue
+		}
+
+		if s.FromBase {
+			acc = g.Accuracy(a, b)
+		}
+
+		if st == StatusFallback || st == StatusNaN {
+			acc = Accuracy{Lon: math.NaN(), Lat: math.NaN()}
+		}
+
+		lon += acc.Lon * acc.Lon
+		lat += acc.Lat * acc.Lat
+		grids++
+	}
+
+	if grids == 0 {
+		return a, b, c, Accuracy{Lon: math.NaN(), Lat: math.NaN()}, status, nil
+	}
+
+	return a, b, c, Accuracy{Lon: math.Sqrt(lon), Lat: math.Sqrt(lat)}, status, nil
+}
+
+func (t Transformer) Func() Func {
+	return func(a, b, c float64) (float64, float64, float64) {
+		a, b, c, _ = t.Transform(a, b, c)
+
+		return a, b, c
+	}
+}
+
+// Slice transforms the coordinates in place by applying each step to the
+// whole slices. zs may be nil. The steps work on copies, so the slices are
+// unchanged if an error is returned.
+func (t Transformer) Slice(xs, ys, zs []float64) error {
+	if len(ys) != len(xs) || (zs != nil && len(zs) != len(xs)) {
+		return fmt.Errorf("slices of different length: %d, %d, %d", len(xs), len(ys), len(zs))
+	}
+
+	sx, sy, sz := make([]float64, len(xs)), make([]float64, len(xs)), make([]float64, len(xs))
+
+	copy(sx, xs)
+	copy(sy, ys)
+	copy(sz, zs)
+
+	if err := t.slice(sx, sy, sz); err != nil {
+		return err
+	}
+
+	copy(xs, sx)
+	copy(ys, sy)
+	copy(zs, sz)
+
+	return nil
+}
+
+func (t Transformer) slice(xs, ys, zs []float64) error {
+	for _, s := range t.pipeline.steps {
+		if err := s.slice(xs, ys, zs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Interleaved transforms coordinates like x0, y0, (z0,) x1, y1, (z1,) ... in place.
+// dim is either 2 or 3. The coordinates are unchanged if an error is returned.
+func (t Transformer) Interleaved(coords []float64, dim int) error {
+	if dim != 2 && dim != 3 {
+		return fmt.Errorf("invalid dimension %d", dim)
+	}
+
+	if len(coords)%dim != 0 {
+		return fmt.Errorf("%d coordinates are not a multiple of dimension %d", len(coords), dim)
+	}
+
+	n := len(coords) / dim
+	xs, ys, zs := make([]float64, n), make([]float64, n), make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		xs[i], ys[i] = coords[i*dim], coords[i*dim+1]
+
+		if dim > 2 {
+			zs[i] = coords[i*dim+2]
+		}
+	}
+
+	if err := t.slice(xs, ys, zs); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		coords[i*dim], coords[i*dim+1] = xs[i], ys[i]
+
+		if dim > 2 {
+			coords[i*dim+2] = zs[i]
+		}
+	}
+
+	return nil
+}
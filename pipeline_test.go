@@ -0,0 +1,228 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTransformerSlice(t *testing.T) {
+	t.Parallel()
+
+	crs, err := NTv1File("testdata/ntv1.dat", NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewTransformer(crs, crs.Base())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		err    error
+	}{
+		{"inside", []float64{-75.1, -75.2}, []float64{45.1, 45.2}, nil},
+		{"outside", []float64{-75.1, -80}, []float64{45.1, 45.2}, ErrOutsideDomain},
+	}
+
+	for _, tt := range tests {
+		xs, ys := append([]float64{}, tt.xs...), append([]float64{}, tt.ys...)
+
+		err := tr.Slice(xs, ys, nil)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error %v, want %v", tt.name, err, tt.err)
+		}
+
+		for i := range xs {
+			changed := xs[i] != tt.xs[i] || ys[i] != tt.ys[i]
+			if changed != (tt.err == nil) {
+				t.Errorf("%s: point %d changed %t", tt.name, i, changed)
+			}
+		}
+	}
+}
+
+func TestInterleavedDimension(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewTransformer(EPSG(4326), EPSG(3857))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		coords []float64
+		dim    int
+		valid  bool
+	}{
+		{[]float64{1, 2, 3, 4}, 2, true},
+		{[]float64{1, 2, 3, 4, 5, 6}, 3, true},
+		{[]float64{1, 2, 3}, 2, false},
+		{[]float64{1, 2, 3, 4}, 3, false},
+		{[]float64{1, 2, 3, 4}, 4, false},
+		{[]float64{1, 2}, 1, false},
+	}
+
+	for _, tt := range tests {
+		if err := tr.Interleaved(append([]float64{}, tt.coords...), tt.dim); (err == nil) != tt.valid {
+			t.Errorf("Transformer.Interleaved(%v, %d): error %v", tt.coords, tt.dim, err)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); (r == nil) != tt.valid {
+					t.Errorf("Func.Interleaved(%v, %d): panic %v", tt.coords, tt.dim, r)
+				}
+			}()
+
+			Transform(EPSG(4326), EPSG(3857)).Interleaved(append([]float64{}, tt.coords...), tt.dim)
+		}()
+	}
+}
+
+func TestEqualCRS(t *testing.T) {
+	t.Parallel()
+
+	grid, err := NTv1File("testdata/ntv1.dat", NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := NewGridResolver()
+	lazy := resolver.NTv1("ntv1.dat", NewSpheroid(6378206.4, 294.9786982), nil)
+	utm := TransverseMercator(grid, -75, 0, 0.9996, 500000, 0)
+
+	tests := []struct {
+		name  string
+		a, b  CRS
+		equal bool
+	}{
+		{"epsg", EPSG(4326), EPSG(4326), true},
+		{"area", EPSG(4326), WithArea(EPSG(4326), Area{West: -180, South: -90, East: 180, North: 90}), true},
+		{"grid", grid, grid, false},
+		{"grid base", utm, utm, false},
+		{"lazy grid", lazy, lazy, true},
+		{"lazy grids", lazy, resolver.NTv1("ntv1.dat", NewSpheroid(6378206.4, 294.9786982), nil), false},
+		{"nil", nil, EPSG(4326), false},
+	}
+
+	for _, tt := range tests {
+		if equal := equalCRS(tt.a, tt.b); equal != tt.equal {
+			t.Errorf("%s: equal %t, want %t", tt.name, equal, tt.equal)
+		}
+	}
+}
+
+func TestTransformAccuracy(t *testing.T) {
+	t.Parallel()
+
+	clarke := NewSpheroid(6378206.4, 294.9786982)
+
+	// 3x3 nodes between 45N 75W and 45.5N 75.5W with accuracies of 0.1" of
+	// latitude and 0.2" of longitude.
+	grid := func() CRS {
+		records := make([][4]float32, 9)
+		for i := range records {
+			records[i] = [4]float32{0.3, -1.5, 0.1, 0.2}
+		}
+
+		crs, err := NewNTv2(NTv2Header{}, []NTv2Subgrid{{
+			Name: "TEST", SLat: 162000, NLat: 163800, ELong: 270000, WLong: 271800, LatInc: 900, LongInc: 900,
+			Records: records,
+		}}, clarke, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return crs
+	}
+
+	// CTable2 grids have no accuracies.
+	ctable2, err := CTable2File("testdata/ctable2.ct2", clarke, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := grid(), grid()
+	fallback := Geographic(HelmertPV(-8, 160, 176, 0, 0, 0, 0), clarke)
+
+	tests := []struct {
+		name     string
+		from, to CRS
+		lon, lat float64
+		acc      Accuracy
+		status   Status
+	}{
+		// 0.1" and 0.2" at 45.1N on the Clarke 1866 spheroid.
+		{"grid", a, a.Base(), -75.1, 45.1, Accuracy{Lon: 4.372883, Lat: 3.087024}, StatusOK},
+		{"inverse grid", a.Base(), a, -75.1, 45.1, Accuracy{Lon: 4.372883, Lat: 3.087024}, StatusOK},
+		{"two grids", a, b, -75.1, 45.1, Accuracy{Lon: 6.184190, Lat: 4.365711}, StatusOK},
+		{"unknown", ctable2, ctable2.Base(), 10.1, 50.1, Accuracy{Lon: math.NaN(), Lat: math.NaN()}, StatusOK},
+		{"no grid", EPSG(4326), EPSG(3857), 10, 50, Accuracy{Lon: math.NaN(), Lat: math.NaN()}, StatusOK},
+		{
+			"fallback", WithOutOfGrid(a, OutOfGridFallback, fallback), a.Base(), -80, 40,
+			Accuracy{Lon: math.NaN(), Lat: math.NaN()}, StatusFallback,
+		},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, _, acc, status, err := tr.TransformAccuracy(tt.lon, tt.lat, 0)
+		if err != nil || status != tt.status || !nearAccuracy(acc.Lon, tt.acc.Lon) || !nearAccuracy(acc.Lat, tt.acc.Lat) {
+			t.Errorf("%s: %f, %f, %s, %v, want %f, %f, %s", tt.name, acc.Lon, acc.Lat, status, err, tt.acc.Lon, tt.acc.Lat, tt.status)
+		}
+	}
+}
+
+func nearAccuracy(a, b float64) bool {
+	return math.Abs(a-b) < 1e-5 || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestPipelineString(t *testing.T) {
+	t.Parallel()
+
+	want := "inverse TransverseMercator(lon0=9, lat0=0, k0=0.9996, x0=500000, y0=0) | " +
+		"TransverseMercator(lon0=15, lat0=0, k0=0.9996, x0=500000, y0=0)"
+
+	if got := NewPipeline(EPSG(25832), EPSG(25833)).String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := NewPipeline(EPSG(4326), EPSG(4326)).String(); got != "Identity" {
+		t.Errorf("got %q, want Identity", got)
+	}
+}
+
+func TestPipelineInverse(t *testing.T) {
+	t.Parallel()
+
+	pipeline := NewPipeline(EPSG(4326), EPSG(27700))
+	steps, inverse := pipeline.Steps(), pipeline.Inverse().Steps()
+
+	if len(steps) == 0 || len(inverse) != len(steps) {
+		t.Fatalf("%d steps, %d inverse steps", len(steps), len(inverse))
+	}
+
+	for i, s := range steps {
+		inv := inverse[len(inverse)-1-i]
+		if !equalCRS(inv.CRS, s.CRS) || inv.FromBase == s.FromBase {
+			t.Errorf("step %d: inverse of %s is %s", i, s, inv)
+		}
+	}
+
+	if got, want := pipeline.Inverse().String(), NewPipeline(EPSG(27700), EPSG(4326)).String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got, want := pipeline.Inverse().Inverse().String(), pipeline.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
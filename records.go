@@ -0,0 +1,151 @@
+//nolint:varnamelen,gomnd
+package wgs84
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// gridRecords are the records of a subgrid. Records that cannot be read are NaN
+// and err returns the last read error.
+type gridRecords interface {
+	len() int
+	at(i int) [4]float32
+	err() error
+}
+
+type memRecords [][4]float32
+
+func (m memRecords) len() int {
+	return len(m)
+}
+
+func (m memRecords) at(i int) [4]float32 {
+	return m[i]
+}
+
+func (m memRecords) err() error {
+	return nil
+}
+
+const cachedRows = 64
+
+// readerAtRecords reads the records row by row on demand and keeps the least
+// recently used rows in memory.
+type readerAtRecords struct {
+	r      io.ReaderAt
+	offset int64
+	count  int
+	cols   int
+	order  binary.ByteOrder
+
+	mu      sync.Mutex
+	rows    map[int]*list.Element
+	lru     *list.List
+	readErr error
+}
+
+type cachedRow struct {
+	index  int
+	values [][4]float32
+}
+
+func newReaderAtRecords(r io.ReaderAt, offset int64, count, cols int, order binary.ByteOrder) *readerAtRecords {
+	return &readerAtRecords{
+		r:      r,
+		offset: offset,
+		count:  count,
+		cols:   cols,
+		order:  order,
+		rows:   make(map[int]*list.Element, cachedRows),
+		lru:    list.New(),
+	}
+}
+
+func (r *readerAtRecords) len() int {
+	return r.count
+}
+
+func (r *readerAtRecords) at(i int) [4]float32 {
+	values := r.row(i / r.cols)
+	if values == nil {
+		nan := float32(math.NaN())
+
+		return [4]float32{nan, nan, nan, nan}
+	}
+
+	return values[i%r.cols]
+}
+
+func (r *readerAtRecords) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.readErr
+}
+
+// row returns the values of a row. The row is read without holding the lock,
+// so that concurrent reads of different rows do not block each other.
+func (r *readerAtRecords) row(index int) [][4]float32 {
+	if values, ok := r.cached(index); ok {
+		return values
+	}
+
+	buf := make([]byte, r.cols*16)
+
+	if n, err := r.r.ReadAt(buf, r.offset+int64(index*r.cols)*16); n < len(buf) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		r.mu.Lock()
+		r.readErr = fmt.Errorf("reading row %d: %w", index, err)
+		r.mu.Unlock()
+
+		return nil
+	}
+
+	values := make([][4]float32, r.cols)
+
+	for i := range values {
+		values[i] = decodeRecord(r.order, buf[i*16:])
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.rows[index]; ok {
+		r.lru.MoveToFront(e)
+
+		return e.Value.(*cachedRow).values //nolint:forcetypeassert
+	}
+
+	if r.lru.Len() >= cachedRows {
+		last := r.lru.Back()
+		r.lru.Remove(last)
+		delete(r.rows, last.Value.(*cachedRow).index) //nolint:forcetypeassert
+	}
+
+	r.rows[index] = r.lru.PushFront(&cachedRow{index: index, values: values})
+
+	return values
+}
+
+func (r *readerAtRecords) cached(index int) ([][4]float32, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.rows[index]
+	if !ok {
+		return nil, false
+	}
+
+	r.lru.MoveToFront(e)
+
+	return e.Value.(*cachedRow).values, true //nolint:forcetypeassert
+}
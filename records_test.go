@@ -0,0 +1,62 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var errRead = errors.New("read failed")
+
+// failingReaderAt fails to read beyond limit.
+type failingReaderAt struct {
+	r     io.ReaderAt
+	limit int64
+}
+
+func (f failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > f.limit {
+		return 0, errRead
+	}
+
+	return f.r.ReadAt(p, off)
+}
+
+func TestReaderAtRecords(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := WriteNTv2(&buf, testGrid(t, func(_, _ float64) [4]float32 { return [4]float32{0.3, -1.5, 0.1, 0.1} })); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		limit int64
+		err   error
+	}{
+		{"ok", int64(buf.Len()), nil},
+		{"records", 22 * 16, errRead},
+	}
+
+	for _, tt := range tests {
+		crs, err := NTv2ReaderAt(failingReaderAt{r: bytes.NewReader(buf.Bytes()), limit: tt.limit}, NewSpheroid(6378206.4, 294.9786982), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tr, err := NewTransformer(crs, crs.Base())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tr := range []Transformer{tr, tr.Inverse()} {
+			if _, _, _, err := tr.Transform(-75.1, 45.1, 0); !errors.Is(err, tt.err) {
+				t.Errorf("%s: error %v, want %v", tt.name, err, tt.err)
+			}
+		}
+	}
+}
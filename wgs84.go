@@ -2,12 +2,13 @@
 package wgs84
 
 import (
-	"embed"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"strings"
+	"time"
 )
 
 type CRS interface {
@@ -17,33 +18,24 @@ type CRS interface {
 	FromBase(float64, float64, float64) (float64, float64, float64)
 }
 
-func Transform(from, to CRS) Func {
-	var (
-		toBase   []Func
-		fromBase []Func
-	)
-
-	for {
-		if from == nil {
-			break
-		}
-
-		toBase = append(toBase, from.ToBase)
-
-		from = from.Base()
-	}
-
-	for {
-		if to == nil {
-			break
-		}
-
-		fromBase = append(fromBase, to.FromBase)
+// SafeCRS is implemented by CRSs that are able to report failures instead of
+// returning NaN values. It is used by Transformer if available.
+type SafeCRS interface {
+	CRS
+	SafeToBase(float64, float64, float64) (float64, float64, float64, error)
+	SafeFromBase(float64, float64, float64) (float64, float64, float64, error)
+}
 
-		to = to.Base()
-	}
+// BatchCRS is implemented by CRSs that are able to transform whole slices of
+// coordinates at once. All slices have the same length.
+type BatchCRS interface {
+	CRS
+	ToBaseSlice(xs, ys, zs []float64) error
+	FromBaseSlice(xs, ys, zs []float64) error
+}
 
-	return chainFunc(chainFunc(toBase...), reverseChainFunc(fromBase...))
+func Transform(from, to CRS) Func {
+	return NewPipeline(from, to).Func()
 }
 
 func chainFunc(f ...Func) Func {
@@ -58,25 +50,63 @@ func chainFunc(f ...Func) Func {
 	}
 }
 
-func reverseChainFunc(f ...Func) Func {
+type Func func(float64, float64, float64) (float64, float64, float64)
+
+func (f Func) Round(dec int) Func {
 	return func(a, b, c float64) (float64, float64, float64) {
-		for i := len(f) - 1; i >= 0; i-- {
-			if f[i] != nil {
-				a, b, c = f[i](a, b, c)
-			}
+		a, b, c = f(a, b, c)
+
+		return round(a, dec), round(b, dec), round(c, dec)
+	}
+}
+
+// Slice transforms the coordinates in place. zs may be nil. f is called for
+// each point; use Transformer.Slice to transform whole slices with BatchCRS.
+// Slice panics if the slices have different lengths.
+func (f Func) Slice(xs, ys, zs []float64) {
+	if len(ys) != len(xs) || (zs != nil && len(zs) != len(xs)) {
+		panic(fmt.Sprintf("wgs84: slices of different length: %d, %d, %d", len(xs), len(ys), len(zs)))
+	}
+
+	for i := range xs {
+		var z float64
+
+		if zs != nil {
+			z = zs[i]
 		}
 
-		return a, b, c
+		xs[i], ys[i], z = f(xs[i], ys[i], z)
+
+		if zs != nil {
+			zs[i] = z
+		}
 	}
 }
 
-type Func func(float64, float64, float64) (float64, float64, float64)
+// Interleaved transforms coordinates like x0, y0, (z0,) x1, y1, (z1,) ... in place.
+// dim is either 2 or 3. Interleaved panics if dim is invalid or the length of
+// coords is not a multiple of dim.
+func (f Func) Interleaved(coords []float64, dim int) {
+	if dim != 2 && dim != 3 {
+		panic(fmt.Sprintf("wgs84: invalid dimension %d", dim))
+	}
 
-func (f Func) Round(dec int) Func {
-	return func(a, b, c float64) (float64, float64, float64) {
-		a, b, c = f(a, b, c)
+	if len(coords)%dim != 0 {
+		panic(fmt.Sprintf("wgs84: %d coordinates are not a multiple of dimension %d", len(coords), dim))
+	}
 
-		return round(a, dec), round(b, dec), round(c, dec)
+	for i := 0; i < len(coords); i += dim {
+		var z float64
+
+		if dim > 2 {
+			z = coords[i+2]
+		}
+
+		coords[i], coords[i+1], z = f(coords[i], coords[i+1], z)
+
+		if dim > 2 {
+			coords[i+2] = z
+		}
 	}
 }
 
@@ -92,6 +122,13 @@ func round(val float64, dec int) float64 {
 	return val
 }
 
+var (
+	ErrUnknownCode   = errors.New("unknown code")
+	ErrOutsideDomain = errors.New("outside domain")
+	ErrGridMissing   = errors.New("grid missing")
+	ErrNotConverged  = errors.New("not converged")
+)
+
 type errorCRS struct {
 	err error
 }
@@ -120,6 +157,14 @@ func (errorCRS) FromBase(_, _, _ float64) (float64, float64, float64) {
 	return math.NaN(), math.NaN(), math.NaN()
 }
 
+func (e errorCRS) SafeToBase(_, _, _ float64) (float64, float64, float64, error) {
+	return math.NaN(), math.NaN(), math.NaN(), e.err
+}
+
+func (e errorCRS) SafeFromBase(_, _, _ float64) (float64, float64, float64, error) {
+	return math.NaN(), math.NaN(), math.NaN(), e.err
+}
+
 type Spheroid struct {
 	A, Fi                                          float64
 	A2, F, F2, B, E2, E, E4, E6, Ei, Ei2, Ei3, Ei4 float64
@@ -147,6 +192,36 @@ func NewSpheroid(a, fi float64) Spheroid {
 	return s
 }
 
+func (s Spheroid) String() string {
+	switch {
+	case s.A == 6378137 && s.Fi == 298.257222101:
+		return "GRS80"
+	case s.A == 6378137 && s.Fi == 298.257223563:
+		return "WGS84"
+	case s.A == 6377397.155 && s.Fi == 299.1528128:
+		return "Bessel 1841"
+	case s.A == 6377563.396 && s.Fi == 299.3249646:
+		return "Airy 1830"
+	case s.A == 6377340.189 && s.Fi == 299.3249646:
+		return "Airy Modified 1849"
+	case s.A == 6378388 && s.Fi == 297:
+		return "International 1924"
+	case s.A == 6378206.4 && s.Fi == 294.978698213898:
+		return "Clarke 1866"
+	default:
+		return fmt.Sprintf("Spheroid(a=%.10g, 1/f=%.10g)", s.A, s.Fi)
+	}
+}
+
+// radii returns the meridional radius of curvature and the radius of
+// curvature in the prime vertical at the latitude.
+func (s Spheroid) radii(lat float64) (m, n float64) {
+	sin := math.Sin(radian(lat))
+	w := 1 - s.E2*sin*sin
+
+	return s.A * (1 - s.E2) / math.Pow(w, 1.5), s.A / math.Sqrt(w)
+}
+
 func (s Spheroid) ToXYZ(lon, lat, h float64) (x, y, z float64) {
 	n := s.A / math.Sqrt(1-s.E2*math.Pow(math.Sin(radian(lat)), 2))
 
@@ -188,6 +263,18 @@ func (base) FromBase(x0, y0, z0 float64) (float64, float64, float64) {
 	return x0, y0, z0
 }
 
+func (base) describe(_ bool) string {
+	return "Identity"
+}
+
+func (base) ToBaseSlice(_, _, _ []float64) error {
+	return nil
+}
+
+func (base) FromBaseSlice(_, _, _ []float64) error {
+	return nil
+}
+
 func Geographic(geocentric CRS, spheroid Spheroid) CRS {
 	if geocentric == nil {
 		geocentric = base{}
@@ -220,7 +307,39 @@ func (b geographic) FromBase(x, y, z float64) (lon, lat, h float64) {
 	return b.s.FromXYZ(x, y, z)
 }
 
+func (b geographic) describe(fromBase bool) string {
+	if fromBase {
+		return fmt.Sprintf("ECEF→Geographic (%s)", b.s)
+	}
+
+	return fmt.Sprintf("Geographic→ECEF (%s)", b.s)
+}
+
+func (b geographic) ToBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		xs[i], ys[i], zs[i] = b.s.ToXYZ(xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (b geographic) FromBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		xs[i], ys[i], zs[i] = b.s.FromXYZ(xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+// Helmert returns a Helmert transformation in meters, arc seconds and ppm with
+// rotations in the Position Vector convention. It is equal to HelmertPV.
 func Helmert(tx, ty, tz, rx, ry, rz, ds float64) CRS {
+	return HelmertPV(tx, ty, tz, rx, ry, rz, ds)
+}
+
+// HelmertPV returns a Helmert transformation with rotations in the Position
+// Vector convention (EPSG method 9606), which is used by PROJ (towgs84).
+func HelmertPV(tx, ty, tz, rx, ry, rz, ds float64) CRS {
 	return helmert{
 		tx: tx,
 		ty: ty,
@@ -232,8 +351,27 @@ func Helmert(tx, ty, tz, rx, ry, rz, ds float64) CRS {
 	}
 }
 
+// HelmertCF returns a Helmert transformation with rotations in the Coordinate
+// Frame convention (EPSG method 9607). The rotations have the opposite sign of
+// the Position Vector convention.
+func HelmertCF(tx, ty, tz, rx, ry, rz, ds float64) CRS {
+	return helmert{
+		tx:    tx,
+		ty:    ty,
+		tz:    tz,
+		rx:    rx,
+		ry:    ry,
+		rz:    rz,
+		ds:    ds,
+		frame: true,
+	}
+}
+
+// helmert is a Helmert transformation. If frame is set, the rotations are in
+// the Coordinate Frame convention.
 type helmert struct {
 	tx, ty, tz, rx, ry, rz, ds float64
+	frame                      bool
 }
 
 func (t helmert) Base() CRS {
@@ -244,12 +382,186 @@ func (t helmert) Spheroid() Spheroid {
 	return Spheroid{}
 }
 
+// rotations returns the rotations in the Position Vector convention.
+func (t helmert) rotations() (rx, ry, rz float64) {
+	if t.frame {
+		return -t.rx, -t.ry, -t.rz
+	}
+
+	return t.rx, t.ry, t.rz
+}
+
 func (t helmert) ToBase(x, y, z float64) (x0, y0, z0 float64) {
-	return calcHelmert(x, y, z, t.tx, t.ty, t.tz, t.rx, t.ry, t.rz, t.ds)
+	rx, ry, rz := t.rotations()
+
+	return calcHelmert(x, y, z, t.tx, t.ty, t.tz, rx, ry, rz, t.ds)
 }
 
 func (t helmert) FromBase(x0, y0, z0 float64) (x, y, z float64) {
-	return calcHelmert(x0, y0, z0, -t.tx, -t.ty, -t.tz, -t.rx, -t.ry, -t.rz, -t.ds)
+	rx, ry, rz := t.rotations()
+
+	return calcHelmert(x0, y0, z0, -t.tx, -t.ty, -t.tz, -rx, -ry, -rz, -t.ds)
+}
+
+func (t helmert) describe(fromBase bool) string {
+	name := "Helmert"
+	if t.frame {
+		name = "HelmertCF"
+	}
+
+	return inverse(fmt.Sprintf("%s(tx=%.10g, ty=%.10g, tz=%.10g, rx=%.10g, ry=%.10g, rz=%.10g, ds=%.10g)", name, t.tx, t.ty, t.tz, t.rx, t.ry, t.rz, t.ds), fromBase)
+}
+
+func (t helmert) ToBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		xs[i], ys[i], zs[i] = t.ToBase(xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (t helmert) FromBaseSlice(xs, ys, zs []float64) error {
+	for i := range xs {
+		xs[i], ys[i], zs[i] = t.FromBase(xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+// HelmertParams are the parameters of a Helmert transformation in meters, arc
+// seconds and ppm or their rates per year.
+type HelmertParams struct {
+	Tx, Ty, Tz, Rx, Ry, Rz, Ds float64
+}
+
+// TimeHelmert returns a time-dependent Helmert transformation (EPSG method
+// 1053). The epoch defaults to the reference epoch, see Transformer.AtEpoch.
+func TimeHelmert(params, rates HelmertParams, epoch float64) CRS {
+	return timeHelmert{
+		params: params,
+		rates:  rates,
+		epoch:  epoch,
+		at:     epoch,
+	}
+}
+
+// TimeHelmertCF is like TimeHelmert, but the rotations are in the Coordinate
+// Frame convention (EPSG method 1056).
+func TimeHelmertCF(params, rates HelmertParams, epoch float64) CRS {
+	return timeHelmert{
+		params: params,
+		rates:  rates,
+		epoch:  epoch,
+		at:     epoch,
+		frame:  true,
+	}
+}
+
+type timeHelmert struct {
+	params, rates HelmertParams
+	epoch, at     float64
+	frame         bool
+}
+
+// DecimalYear returns the epoch of a time as decimal year, e.g. 2024.5.
+func DecimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	return float64(t.Year()) + float64(t.Sub(start))/float64(end.Sub(start))
+}
+
+func (t timeHelmert) Base() CRS {
+	return base{}
+}
+
+func (t timeHelmert) Spheroid() Spheroid {
+	return Spheroid{}
+}
+
+// current returns the parameters at the epoch of the coordinates with
+// rotations in the Position Vector convention.
+func (t timeHelmert) current() HelmertParams {
+	dt := t.at - t.epoch
+	p, r := t.params, t.rates
+
+	c := HelmertParams{
+		Tx: p.Tx + r.Tx*dt,
+		Ty: p.Ty + r.Ty*dt,
+		Tz: p.Tz + r.Tz*dt,
+		Rx: p.Rx + r.Rx*dt,
+		Ry: p.Ry + r.Ry*dt,
+		Rz: p.Rz + r.Rz*dt,
+		Ds: p.Ds + r.Ds*dt,
+	}
+
+	if t.frame {
+		c.Rx, c.Ry, c.Rz = -c.Rx, -c.Ry, -c.Rz
+	}
+
+	return c
+}
+
+func (t timeHelmert) ToBase(x, y, z float64) (x0, y0, z0 float64) {
+	p := t.current()
+
+	return calcHelmert(x, y, z, p.Tx, p.Ty, p.Tz, p.Rx, p.Ry, p.Rz, p.Ds)
+}
+
+func (t timeHelmert) FromBase(x0, y0, z0 float64) (x, y, z float64) {
+	p := t.current()
+
+	return calcHelmert(x0, y0, z0, -p.Tx, -p.Ty, -p.Tz, -p.Rx, -p.Ry, -p.Rz, -p.Ds)
+}
+
+func (t timeHelmert) describe(fromBase bool) string {
+	p, r := t.params, t.rates
+
+	name := "TimeHelmert"
+	if t.frame {
+		name = "TimeHelmertCF"
+	}
+
+	return inverse(fmt.Sprintf("%s(tx=%.10g, ty=%.10g, tz=%.10g, rx=%.10g, ry=%.10g, rz=%.10g, ds=%.10g, "+
+		"dtx=%.10g, dty=%.10g, dtz=%.10g, drx=%.10g, dry=%.10g, drz=%.10g, dds=%.10g, t0=%.10g, t=%.10g)",
+		name, p.Tx, p.Ty, p.Tz, p.Rx, p.Ry, p.Rz, p.Ds, r.Tx, r.Ty, r.Tz, r.Rx, r.Ry, r.Rz, r.Ds, t.epoch, t.at), fromBase)
+}
+
+func (t timeHelmert) ToBaseSlice(xs, ys, zs []float64) error {
+	p := t.current()
+
+	for i := range xs {
+		xs[i], ys[i], zs[i] = calcHelmert(xs[i], ys[i], zs[i], p.Tx, p.Ty, p.Tz, p.Rx, p.Ry, p.Rz, p.Ds)
+	}
+
+	return nil
+}
+
+func (t timeHelmert) FromBaseSlice(xs, ys, zs []float64) error {
+	p := t.current()
+
+	for i := range xs {
+		xs[i], ys[i], zs[i] = calcHelmert(xs[i], ys[i], zs[i], -p.Tx, -p.Ty, -p.Tz, -p.Rx, -p.Ry, -p.Rz, -p.Ds)
+	}
+
+	return nil
+}
+
+// atEpoch sets the epoch of the coordinates of time-dependent CRSs.
+func atEpoch(crs CRS, epoch float64) CRS {
+	switch c := crs.(type) {
+	case bounded:
+		c.crs = atEpoch(c.crs, epoch)
+
+		return c
+	case timeHelmert:
+		c.at = epoch
+
+		return c
+	default:
+		return crs
+	}
 }
 
 const (
@@ -257,6 +569,8 @@ const (
 	ppm  = 0.000001
 )
 
+// calcHelmert applies a Helmert transformation with rotations in the Position
+// Vector convention.
 func calcHelmert(x, y, z, tx, ty, tz, rx, ry, rz, ds float64) (x0, y0, z0 float64) {
 	x0 = (1+ds*ppm)*(x+z*ry*asec-y*rz*asec) + tx
 	y0 = (1+ds*ppm)*(y+x*rz*asec-z*rx*asec) + ty
@@ -265,110 +579,645 @@ func calcHelmert(x, y, z, tx, ty, tz, rx, ry, rz, ds float64) (x0, y0, z0 float6
 	return
 }
 
-//go:embed ntv2
-var res embed.FS
+// NTv2File loads a NTv2 grid (.gsb) from the file system. The grid shifts
+// coordinates on the spheroid to the base CRS, which defaults to EPSG 4326.
+func NTv2File(path string, spheroid Spheroid, base CRS) (CRS, error) {
+	return gridFile(func(r ...io.Reader) (ntv2, error) {
+		return readNTv2(r[0], spheroid, base)
+	}, path)
+}
+
+// NTv2ReaderAt reads the headers of a NTv2 grid and the records on demand. r
+// must stay open while the CRS is used.
+func NTv2ReaderAt(r io.ReaderAt, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := decodeNTv2(io.NewSectionReader(r, 0, math.MaxInt64), r, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// NTv2 reads a NTv2 grid. The grid shifts coordinates on the spheroid to the
+// base CRS, which defaults to EPSG 4326.
+func NTv2(reader io.Reader, spheroid Spheroid, base CRS) (CRS, error) {
+	data, err := readNTv2(reader, spheroid, base)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func readNTv2(reader io.Reader, spheroid Spheroid, base CRS) (ntv2, error) {
+	return decodeNTv2(reader, nil, spheroid, base)
+}
+
+// decodeNTv2 reads a NTv2 grid. If at is set, reader must be an io.Seeker
+// on the same data and the records are read on demand from at.
+func decodeNTv2(reader io.Reader, at io.ReaderAt, spheroid Spheroid, base CRS) (ntv2, error) {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	data := ntv2{
+		base:     base,
+		spheroid: spheroid,
+	}
+
+	var (
+		set   = make([]byte, 16)
+		count int
+		order binary.ByteOrder = binary.LittleEndian
+		unit                   = 1.0
+	)
+
+	next := func() (string, error) {
+		count++
+
+		if _, err := io.ReadFull(reader, set); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return "", fmt.Errorf("ntv2 record %d: %w", count, err)
+		}
+
+		return toString(set[:8]), nil
+	}
+
+	key, err := next()
+	if err != nil {
+		return ntv2{}, err
+	}
+
+	if key != "NUM_OREC" {
+		return ntv2{}, fmt.Errorf("invalid ntv2 header: %q", key)
+	}
+
+	if binary.LittleEndian.Uint32(set[8:]) != 11 {
+		order = binary.BigEndian
+	}
+
+	data.numOrec = int32(order.Uint32(set[8:]))
+
+	for i := 1; i < int(data.numOrec); i++ {
+		key, err = next()
+		if err != nil {
+			return ntv2{}, err
+		}
+
+		switch key {
+		case "NUM_SREC":
+			data.numSrec = int32(order.Uint32(set[8:]))
+		case "NUM_FILE":
+			data.numFile = int32(order.Uint32(set[8:]))
+		case "GS_TYPE":
+			data.gsType = toString(set[8:])
+
+			switch data.gsType {
+			case "SECONDS":
+				unit = 1
+			case "MINUTES":
+				unit = 60
+			case "DEGREES":
+				unit = 3600
+			default:
+				return ntv2{}, fmt.Errorf("invalid ntv2 GS_TYPE %q", data.gsType)
+			}
+		case "VERSION":
+			data.version = toString(set[8:])
+		case "SYSTEM_F":
+			data.systemF = toString(set[8:])
+		case "SYSTEM_T":
+			data.systemT = toString(set[8:])
+		case "MAJOR_F":
+			data.majorF = toFloat(order, set[8:])
+		case "MINOR_F":
+			data.minorF = toFloat(order, set[8:])
+		case "MAJOR_T":
+			data.majorT = toFloat(order, set[8:])
+		case "MINOR_T":
+			data.minorT = toFloat(order, set[8:])
+		}
+	}
+
+	if data.numFile <= 0 {
+		return ntv2{}, fmt.Errorf("invalid ntv2 header: NUM_FILE %d", data.numFile)
+	}
+
+	named := make(map[string]*ntv2Grid, data.numFile)
+
+	for f := 0; f < int(data.numFile); f++ {
+		grid := &ntv2Grid{}
+
+		for i := 0; i < int(data.numSrec); i++ {
+			key, err = next()
+			if err != nil {
+				return ntv2{}, err
+			}
+
+			switch key {
+			case "SUB_NAME":
+				grid.name = toString(set[8:])
+			case "PARENT":
+				grid.parent = toString(set[8:])
+			case "CREATED":
+				grid.created = toString(set[8:])
+			case "UPDATED":
+				grid.updated = toString(set[8:])
+			case "S_LAT":
+				grid.header[0] = toFloat(order, set[8:])
+			case "N_LAT":
+				grid.header[1] = toFloat(order, set[8:])
+			case "E_LONG":
+				grid.header[2] = toFloat(order, set[8:])
+			case "W_LONG":
+				grid.header[3] = toFloat(order, set[8:])
+			case "LAT_INC":
+				grid.header[4] = toFloat(order, set[8:])
+			case "LONG_INC":
+				grid.header[5] = toFloat(order, set[8:])
+			case "GS_COUNT":
+				grid.gsCount = int32(order.Uint32(set[8:]))
+			}
+		}
+
+		grid.unit = unit
+		grid.sLat, grid.nLat = grid.header[0]*unit, grid.header[1]*unit
+		grid.eLong, grid.wLong = grid.header[2]*unit, grid.header[3]*unit
+		grid.latInc, grid.longInc = grid.header[4]*unit, grid.header[5]*unit
+
+		if grid.latInc <= 0 || grid.longInc <= 0 || grid.gsCount != int32(grid.rows()*grid.cols()) {
+			return ntv2{}, fmt.Errorf("invalid ntv2 subgrid %q: GS_COUNT %d, LAT_INC %f, LONG_INC %f", grid.name, grid.gsCount, grid.latInc, grid.longInc)
+		}
+
+		if seeker, ok := reader.(io.Seeker); ok && at != nil {
+			grid.records = newReaderAtRecords(at, int64(count)*16, int(grid.gsCount), grid.cols(), order)
+
+			if _, err = seeker.Seek(int64(grid.gsCount)*16, io.SeekCurrent); err != nil {
+				return ntv2{}, err
+			}
+
+			count += int(grid.gsCount)
+		} else {
+			values := make(memRecords, grid.gsCount)
+
+			for i := range values {
+				if _, err = next(); err != nil {
+					return ntv2{}, err
+				}
+
+				values[i] = decodeRecord(order, set)
+			}
+
+			grid.records = values
+		}
+
+		if grid.parent == "NONE" || grid.parent == "" {
+			data.grids = append(data.grids, grid)
+		} else {
+			parent, ok := named[grid.parent]
+			if !ok {
+				return ntv2{}, fmt.Errorf("invalid ntv2 subgrid %q: parent %q not found", grid.name, grid.parent)
+			}
+
+			parent.children = append(parent.children, grid)
+		}
+
+		named[grid.name] = grid
+
+		if data.name == "" {
+			data.name = grid.name
+		}
+	}
+
+	// The value of the END record and any padding are kept to write the file
+	// unchanged.
+	if key, err = next(); err == nil && key == "END" {
+		data.trailer = append([]byte(nil), set[8:]...)
+
+		rest, _ := io.ReadAll(io.LimitReader(reader, 1<<10))
+		data.trailer = append(data.trailer, rest...)
+	}
+
+	return data, nil
+}
+
+func decodeRecord(order binary.ByteOrder, b []byte) [4]float32 {
+	return [4]float32{toFloat32(order, b[0:4]), toFloat32(order, b[4:8]), toFloat32(order, b[8:12]), toFloat32(order, b[12:16])}
+}
+
+func toFloat32(order binary.ByteOrder, b []byte) float32 {
+	i := order.Uint32(b)
+
+	return math.Float32frombits(i)
+}
+
+func toFloat(order binary.ByteOrder, b []byte) float64 {
+	i := order.Uint64(b)
+
+	return math.Float64frombits(i)
+}
+
+func toString(b []byte) string {
+	return strings.TrimSpace(string(b))
+}
+
+// ntv2 is a grid shift. Grids of other formats are converted to NTv2 subgrids
+// and format is set.
+type ntv2 struct {
+	format        string
+	name          string
+	spheroid      Spheroid
+	base          CRS
+	numOrec       int32
+	numSrec       int32
+	numFile       int32
+	gsType        string
+	version       string
+	systemF       string
+	systemT       string
+	majorF        float64
+	minorF        float64
+	majorT        float64
+	minorT        float64
+	grids         []*ntv2Grid
+	trailer       []byte
+	policy        OutOfGrid
+	fallback      Transformer
+	interpolation Interpolation
+	tolerance     float64
+}
+
+// Interpolation is the interpolation method of grid shifts.
+type Interpolation int
+
+const (
+	// InterpolationBilinear interpolates the 4 surrounding nodes.
+	InterpolationBilinear Interpolation = iota
+	// InterpolationBiquadratic interpolates the 9 nearest nodes with quadratic
+	// polynomials.
+	InterpolationBiquadratic
+	// InterpolationBicubic interpolates the 16 surrounding nodes by cubic
+	// convolution (Catmull-Rom).
+	InterpolationBicubic
+)
+
+func (i Interpolation) String() string {
+	switch i {
+	case InterpolationBilinear:
+		return "bilinear"
+	case InterpolationBiquadratic:
+		return "biquadratic"
+	case InterpolationBicubic:
+		return "bicubic"
+	default:
+		return fmt.Sprintf("Interpolation(%d)", int(i))
+	}
+}
+
+// WithInterpolation sets the interpolation method of a grid shift. Points
+// outside of the grid are always extrapolated bilinearly.
+func WithInterpolation(crs CRS, method Interpolation) CRS {
+	switch c := crs.(type) {
+	case bounded:
+		c.crs = WithInterpolation(c.crs, method)
+
+		return c
+	case lazyGrid:
+		return c.withInterpolation(method)
+	case ntv2:
+		c.interpolation = method
+
+		return c
+	default:
+		return crs
+	}
+}
+
+// DefaultTolerance is the tolerance in degrees of iterative calculations,
+// which is about 0.1 millimeters.
+const DefaultTolerance = 1e-9
+
+// maxIterations limits iterative calculations, which report ErrNotConverged
+// if the tolerance is not reached.
+const maxIterations = 20
+
+// WithTolerance sets the tolerance in degrees of iterative calculations.
+// Non-positive values select DefaultTolerance.
+func WithTolerance(crs CRS, tolerance float64) CRS {
+	switch c := crs.(type) {
+	case bounded:
+		c.crs = WithTolerance(c.crs, tolerance)
+
+		return c
+	case unit:
+		c.base = WithTolerance(c.base, tolerance)
+
+		return c
+	case lazyGrid:
+		return c.withTolerance(tolerance)
+	case ntv2:
+		c.tolerance = tolerance
+
+		return c
+	case transverseMercator:
+		c.tolerance = tolerance
+
+		return c
+	case krovak:
+		c.tolerance = tolerance
+
+		return c
+	case molodensky:
+		c.tolerance = tolerance
+
+		return c
+	default:
+		return crs
+	}
+}
+
+func tolerance(t float64) float64 {
+	if t <= 0 {
+		return DefaultTolerance
+	}
+
+	return t
+}
+
+// OutOfGrid is the behaviour of grid shifts for points outside of the grid.
+type OutOfGrid int
+
+const (
+	// OutOfGridError returns ErrOutsideDomain through a Transformer. ToBase and
+	// FromBase, and thus Func, extrapolate the shift of the nearest grid.
+	OutOfGridError OutOfGrid = iota
+	// OutOfGridNaN returns NaN values without an error.
+	OutOfGridNaN
+	// OutOfGridFallback transforms the point with the fallback CRS.
+	OutOfGridFallback
+	// OutOfGridExtrapolate extrapolates the shift of the nearest grid.
+	OutOfGridExtrapolate
+)
+
+// WithOutOfGrid sets the behaviour of a grid shift for points outside of the
+// grid. The fallback CRS has the same coordinates as the grid (e.g.
+// Geographic(Helmert(...), spheroid)) and is only used by OutOfGridFallback.
+func WithOutOfGrid(crs CRS, policy OutOfGrid, fallback CRS) CRS {
+	switch c := crs.(type) {
+	case bounded:
+		c.crs = WithOutOfGrid(c.crs, policy, fallback)
+
+		return c
+	case lazyGrid:
+		return c.withOutOfGrid(policy, fallback)
+	case ntv2:
+		c.policy = policy
+
+		if policy == OutOfGridFallback {
+			t, err := NewTransformer(fallback, c.base)
+			if err != nil {
+				return errorCRS{err: err}
+			}
+
+			c.fallback = t
+		}
+
+		return c
+	default:
+		return crs
+	}
+}
+
+// ntv2Grid is a subgrid of a NTv2 file. All values are in seconds and
+// longitudes are positive west. The records start in the south-east corner
+// and go row by row from east to west.
+type ntv2Grid struct {
+	name     string
+	parent   string
+	created  string
+	updated  string
+	sLat     float64
+	nLat     float64
+	eLong    float64
+	wLong    float64
+	latInc   float64
+	longInc  float64
+	gsCount  int32
+	records  gridRecords
+	children []*ntv2Grid
+	// unit is the number of seconds of the values of the records and the
+	// header, e.g. 60 for GS_TYPE MINUTES. Zero means seconds.
+	unit float64
+	// header are S_LAT to LONG_INC in the unit to write them unchanged.
+	header [6]float64
+}
+
+func (g *ntv2Grid) seconds() float64 {
+	if g.unit == 0 {
+		return 1
+	}
+
+	return g.unit
+}
+
+func (g *ntv2Grid) String() string {
+	return fmt.Sprintf("SUB_NAME: %s, PARENT: %s, S_LAT: %f, N_LAT: %f, E_LONG: %f, W_LONG: %f, LAT_INC: %f, LONG_INC: %f, GS_COUNT: %d", g.name, g.parent, g.sLat, g.nLat, g.eLong, g.wLong, g.latInc, g.longInc, g.gsCount)
+}
+
+func (g *ntv2Grid) cols() int {
+	return int(math.Floor((g.wLong-g.eLong)/g.longInc+0.5)) + 1
+}
+
+func (g *ntv2Grid) rows() int {
+	return int(math.Floor((g.nLat-g.sLat)/g.latInc+0.5)) + 1
+}
+
+func (g *ntv2Grid) contains(lonW, lat float64) bool {
+	return lat >= g.sLat && lat <= g.nLat && lonW >= g.eLong && lonW <= g.wLong
+}
+
+// find returns the densest subgrid containing the point.
+func (g *ntv2Grid) find(lonW, lat float64) *ntv2Grid {
+	for _, child := range g.children {
+		if child.contains(lonW, lat) {
+			return child.find(lonW, lat)
+		}
+	}
+
+	return g
+}
+
+// distance returns the distance of the point to the grid in seconds.
+func (g *ntv2Grid) distance(lonW, lat float64) float64 {
+	dlon := max(g.eLong-lonW, lonW-g.wLong, 0)
+	dlat := max(g.sLat-lat, lat-g.nLat, 0)
+
+	return math.Hypot(dlon, dlat)
+}
+
+// shift interpolates the shift. Points outside of the grid are extrapolated
+// linearly from the nearest edge cell.
+func (g *ntv2Grid) shift(lonW, lat float64, method Interpolation) (slonW, slat float64) {
+	v := g.interpolate(lonW, lat, method)
+
+	return v[1] * g.seconds(), v[0] * g.seconds()
+}
+
+// interpolate returns the interpolation of all four columns.
+func (g *ntv2Grid) interpolate(lonW, lat float64, method Interpolation) (v [4]float64) {
+	cols, rows := g.cols(), g.rows()
+
+	if method != InterpolationBilinear && cols > 2 && rows > 2 && g.contains(lonW, lat) {
+		return g.polynomial(lonW, lat, method)
+	}
+
+	fcol := (lonW - g.eLong) / g.longInc
+	frow := (lat - g.sLat) / g.latInc
+
+	col := min(max(math.Floor(fcol), 0), float64(max(cols-2, 0)))
+	row := min(max(math.Floor(frow), 0), float64(max(rows-2, 0)))
+
+	dx := fcol - col
+	dy := frow - row
+
+	se := int(row)*cols + int(col)
+	sw := min(se+1, g.records.len()-1)
+	ne := min(se+cols, g.records.len()-1)
+	nw := min(ne+1, g.records.len()-1)
+
+	sse, ssw, sne, snw := g.records.at(se), g.records.at(sw), g.records.at(ne), g.records.at(nw)
+
+	for i := range v {
+		v[i] = (1-dx)*(1-dy)*float64(sse[i]) + dx*(1-dy)*float64(ssw[i]) + (1-dx)*dy*float64(sne[i]) + dx*dy*float64(snw[i])
+	}
+
+	return v
+}
+
+// polynomial interpolates the 3x3 (biquadratic) or 4x4 (bicubic) nearest
+// nodes. Nodes beyond the edges are extrapolated linearly.
+func (g *ntv2Grid) polynomial(lonW, lat float64, method Interpolation) (v [4]float64) {
+	cols, rows := g.cols(), g.rows()
+
+	fcol := (lonW - g.eLong) / g.longInc
+	frow := (lat - g.sLat) / g.latInc
+
+	weights := cubicWeights
+	first := math.Floor
+
+	if method == InterpolationBiquadratic {
+		weights = quadraticWeights
+		first = math.Round
+	}
+
+	col, row := first(fcol), first(frow)
+	wx, wy := weights(fcol-col), weights(frow-row)
+
+	for j, w := range wy {
+		for i, wi := range wx {
+			rec := g.node(int(col)+i-1, int(row)+j-1, cols, rows)
+
+			for k := range v {
+				v[k] += w * wi * rec[k]
+			}
+		}
+	}
+
+	return v
+}
+
+// node returns the values of a node. Nodes beyond the edges are extrapolated
+// linearly from the two nearest nodes.
+func (g *ntv2Grid) node(col, row, cols, rows int) (v [4]float64) {
+	if c := min(max(col, 0), cols-1); c != col {
+		return extrapolate(g.node(c, row, cols, rows), g.node(c+inward(col), row, cols, rows), col-c)
+	}
+
+	if r := min(max(row, 0), rows-1); r != row {
+		return extrapolate(g.node(col, r, cols, rows), g.node(col, r+inward(row), cols, rows), row-r)
+	}
+
+	rec := g.records.at(row*cols + col)
+
+	for k := range v {
+		v[k] = float64(rec[k])
+	}
+
+	return v
+}
 
-func loadNTv2(name string, spheroid Spheroid, base CRS) CRS {
-	file, err := res.Open("ntv2/" + name)
-	if err != nil {
-		return errorCRS{err: err}
+// inward returns the direction from an index beyond the edges into the grid.
+func inward(i int) int {
+	if i < 0 {
+		return 1
 	}
 
-	crs := loadReaderNTv2(file, spheroid, base)
-
-	return crs
+	return -1
 }
 
-func loadReaderNTv2(reader io.Reader, spheroid Spheroid, base CRS) CRS {
-	if base == nil {
-		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
-	}
+func extrapolate(edge, inner [4]float64, distance int) (v [4]float64) {
+	d := math.Abs(float64(distance))
 
-	data := ntv2{
-		base:     base,
-		spheroid: spheroid,
+	for k := range v {
+		v[k] = edge[k] + d*(edge[k]-inner[k])
 	}
 
-	for i := 1; ; i++ {
-		set := make([]byte, 16)
+	return v
+}
 
-		_, err := reader.Read(set)
-		if err != nil {
-			return errorCRS{err: err}
-		}
+// quadraticWeights are the Lagrange weights of the nodes -1, 0 and 1 for
+// -0.5 <= t <= 0.5.
+func quadraticWeights(t float64) []float64 {
+	return []float64{t * (t - 1) / 2, 1 - t*t, t * (t + 1) / 2}
+}
 
-		switch toString(set[:8]) {
-		case "NUM_OREC":
-			data.numOrec = int32(binary.LittleEndian.Uint32(set[8:]))
-		case "NUM_SREC":
-			data.numSrec = int32(binary.LittleEndian.Uint32(set[8:]))
-		case "NUM_FILE":
-			data.numFile = int32(binary.LittleEndian.Uint32(set[8:]))
-		case "S_LAT":
-			data.sLat = toFloat(set[8:])
-		case "N_LAT":
-			data.nLat = toFloat(set[8:])
-		case "E_LONG":
-			data.eLong = toFloat(set[8:])
-		case "W_LONG":
-			data.wLong = toFloat(set[8:])
-		case "LAT_INC":
-			data.latInc = toFloat(set[8:])
-		case "LONG_INC":
-			data.longInc = toFloat(set[8:])
-		case "GS_COUNT":
-			data.gsCount = int32(binary.LittleEndian.Uint32(set[8:]))
-		default:
-			offset := int(data.numOrec) + int(data.numSrec) + int(data.numFile)
-			if i >= int(data.gsCount)+offset {
-				return data
-			}
+// cubicWeights are the Catmull-Rom weights of the nodes -1, 0, 1 and 2 for
+// 0 <= t < 1.
+func cubicWeights(t float64) []float64 {
+	t2, t3 := t*t, t*t*t
 
-			if i >= offset && i < int(data.gsCount)+offset {
-				data.values = append(data.values, [4]float32{
-					toFloat32(set[0:4]), toFloat32(set[4:8]), toFloat32(set[8:12]), toFloat32(set[12:16]),
-				})
+	return []float64{(-t3 + 2*t2 - t) / 2, (3*t3 - 5*t2 + 2) / 2, (-3*t3 + 4*t2 + t) / 2, (t3 - t2) / 2}
+}
 
-				continue
-			}
+func (n ntv2) String() string {
+	grids := make([]string, 0, n.numFile)
+
+	var walk func([]*ntv2Grid)
+
+	walk = func(gs []*ntv2Grid) {
+		for _, g := range gs {
+			grids = append(grids, "{"+g.String()+"}")
+			walk(g.children)
 		}
 	}
-}
 
-func toFloat32(b []byte) float32 {
-	i := binary.LittleEndian.Uint32(b)
+	walk(n.grids)
 
-	return math.Float32frombits(i)
+	return fmt.Sprintf("BASE: %v, NUM_OREC: %d, NUM_SREC: %d, NUM_FILE: %d, GS_TYPE: %s, SYSTEM_F: %s, SYSTEM_T: %s, SUBGRIDS: [%s]", n.base, n.numOrec, n.numSrec, n.numFile, n.gsType, n.systemF, n.systemT, strings.Join(grids, ", "))
 }
 
-func toFloat(b []byte) float64 {
-	i := binary.LittleEndian.Uint64(b)
+func (n ntv2) describe(fromBase bool) string {
+	if len(n.grids) == 0 && n.policy == OutOfGridFallback {
+		if fromBase {
+			return n.fallback.Inverse().Pipeline().String()
+		}
 
-	return math.Float64frombits(i)
-}
+		return n.fallback.Pipeline().String()
+	}
 
-func toString(b []byte) string {
-	return strings.TrimSpace(string(b))
-}
+	format := n.format
+	if format == "" {
+		format = "NTv2"
+	}
 
-type ntv2 struct {
-	spheroid Spheroid
-	base     CRS
-	numOrec  int32
-	numSrec  int32
-	numFile  int32
-	gsCount  int32
-	sLat     float64
-	nLat     float64
-	eLong    float64
-	wLong    float64
-	latInc   float64
-	longInc  float64
-	values   [][4]float32
-}
+	if n.interpolation != InterpolationBilinear {
+		format += " " + n.interpolation.String()
+	}
 
-func (n ntv2) String() string {
-	return fmt.Sprintf("BASE: %v, NUM_OREC: %d, NUM_SREC: %d, NUM_FILE: %d, S_LAT: %f, N_LAT: %f, E_LONG: %f, W_LONG: %f, LAT_INC: %f, LONG_INC: %f, GS_COUNT: %d", n.base, n.numOrec, n.numSrec, n.numFile, n.sLat, n.nLat, n.eLong, n.wLong, n.latInc, n.longInc, n.gsCount)
+	return inverse(format+" "+n.name, fromBase)
 }
 
 func (n ntv2) Base() CRS {
@@ -380,77 +1229,225 @@ func (n ntv2) Spheroid() Spheroid {
 }
 
 func (n ntv2) ToBase(lon, lat, h float64) (lon2, lat2, h2 float64) {
-	slon, slat := n.Shift(-lon, lat)
+	lon2, lat2, h2, _, _ = n.extrapolating().report(false, lon, lat, h)
 
-	return lon + slon, lat + slat, h
+	return lon2, lat2, h2
 }
 
 func (n ntv2) FromBase(lon, lat, h float64) (lon2, lat2, h2 float64) {
+	lon2, lat2, h2, _, _ = n.extrapolating().report(true, lon, lat, h)
+
+	return lon2, lat2, h2
+}
+
+// extrapolating returns the grid for ToBase and FromBase, which cannot report
+// errors and extrapolate instead of OutOfGridError.
+func (n ntv2) extrapolating() ntv2 {
+	if n.policy == OutOfGridError {
+		n.policy = OutOfGridExtrapolate
+	}
+
+	return n
+}
+
+func (n ntv2) SafeToBase(lon, lat, h float64) (lon2, lat2, h2 float64, err error) {
+	lon2, lat2, h2, _, err = n.report(false, lon, lat, h)
+
+	return lon2, lat2, h2, err
+}
+
+func (n ntv2) SafeFromBase(lon, lat, h float64) (lon2, lat2, h2 float64, err error) {
+	lon2, lat2, h2, _, err = n.report(true, lon, lat, h)
+
+	return lon2, lat2, h2, err
+}
+
+func (n ntv2) report(fromBase bool, lon, lat, h float64) (float64, float64, float64, Status, error) {
+	status := StatusOK
+
+	if _, inside := n.grid(-lon*3600, lat*3600); !inside {
+		switch n.policy {
+		case OutOfGridNaN:
+			return math.NaN(), math.NaN(), math.NaN(), StatusNaN, nil
+		case OutOfGridFallback:
+			t := n.fallback
+			if fromBase {
+				t = t.Inverse()
+			}
+
+			lon, lat, h, err := t.Transform(lon, lat, h)
+
+			return lon, lat, h, StatusFallback, err
+		case OutOfGridExtrapolate:
+			status = StatusExtrapolated
+		default:
+			return math.NaN(), math.NaN(), math.NaN(), StatusOK, fmt.Errorf("%w: %f, %f is outside of grid %s", ErrOutsideDomain, lon, lat, n.name)
+		}
+	}
+
+	if !fromBase {
+		slon, slat := n.Shift(lon, lat)
+		if math.IsNaN(slon + slat) {
+			return math.NaN(), math.NaN(), math.NaN(), StatusNaN, n.readErr(lon, lat)
+		}
+
+		return lon + slon, lat + slat, h, status, nil
+	}
+
 	qlat := lat
 	qlon := lon
+	tol := tolerance(n.tolerance)
 
-	for i := 0; i < 4; i++ {
+	for i := 0; i < maxIterations; i++ {
 		slon, slat := n.Shift(qlon, qlat)
+		if math.IsNaN(slon + slat) {
+			return math.NaN(), math.NaN(), math.NaN(), StatusNaN, n.readErr(qlon, qlat)
+		}
+
+		dlon, dlat := lon-slon-qlon, lat-slat-qlat
+		qlon += dlon
+		qlat += dlat
+
+		if math.Abs(dlon) <= tol && math.Abs(dlat) <= tol {
+			return qlon, qlat, h, status, nil
+		}
+	}
+
+	return math.NaN(), math.NaN(), math.NaN(), status, fmt.Errorf("%w: inverse of grid %s at %f, %f", ErrNotConverged, n.name, lon, lat)
+}
+
+// readErr returns the read error of the records of the grid of the point.
+func (n ntv2) readErr(lon, lat float64) error {
+	g, _ := n.grid(-lon*3600, lat*3600)
+	if g == nil {
+		return nil
+	}
+
+	if err := g.records.err(); err != nil {
+		return fmt.Errorf("grid %s: %w", n.name, err)
+	}
+
+	return nil
+}
+
+// grid returns the densest subgrid containing the point. If no subgrid
+// contains the point, the nearest top-level grid is returned.
+func (n ntv2) grid(lonW, lat float64) (*ntv2Grid, bool) {
+	var nearest *ntv2Grid
+
+	for _, g := range n.grids {
+		if g.contains(lonW, lat) {
+			return g.find(lonW, lat), true
+		}
 
-		qlon = lon - slon
-		qlat = lat - slat
+		if nearest == nil || g.distance(lonW, lat) < nearest.distance(lonW, lat) {
+			nearest = g
+		}
 	}
 
-	return qlon, qlat, h
+	return nearest, false
 }
 
+// Shift returns the shift in degrees at the point. Points outside of the grid
+// are extrapolated.
 func (n ntv2) Shift(lon, lat float64) (float64, float64) {
-	fcol := (-lon*3600 - n.eLong) / n.longInc
-	frow := (lat*3600 - n.sLat) / n.latInc
+	lonW, latS := -lon*3600, lat*3600
+
+	g, _ := n.grid(lonW, latS)
+	if g == nil {
+		return math.NaN(), math.NaN()
+	}
 
-	col := math.Floor(fcol)
-	row := math.Floor(frow)
+	slonW, slat := g.shift(lonW, latS, n.interpolation)
 
-	ppr := math.Floor((n.wLong-n.eLong)/n.longInc+0.5) + 1
-	ppc := math.Floor((n.nLat-n.sLat)/n.latInc+0.5) + 1
+	return -slonW / 3600, slat / 3600
+}
 
-	se := row*ppr + col
-	sw := se + 1
-	ne := se + ppr
-	nw := ne + 1
+// Accuracy returns the interpolated accuracy of the grid at the point in
+// meters. Negative values of the grid file mean unknown and are returned as
+// NaN.
+func (n ntv2) Accuracy(lon, lat float64) Accuracy {
+	lonW, latS := -lon*3600, lat*3600
 
-	if col >= ppr-1 {
-		sw = se
-		nw = ne
+	g, _ := n.grid(lonW, latS)
+	if g == nil {
+		return Accuracy{Lon: math.NaN(), Lat: math.NaN()}
 	}
 
-	if row >= ppc-1 {
-		ne = se
-		nw = sw
+	v := g.interpolate(lonW, latS, n.interpolation)
+	v[2], v[3] = v[2]*g.seconds(), v[3]*g.seconds()
+
+	m, nu := n.spheroid.radii(lat)
+
+	acc := Accuracy{
+		Lon: radian(v[3]/3600) * nu * math.Cos(radian(lat)),
+		Lat: radian(v[2]/3600) * m,
 	}
 
-	if col <= 0 {
-		se = sw
-		ne = nw
+	if v[3] < 0 {
+		acc.Lon = math.NaN()
 	}
 
-	if row <= 0 {
-		se = ne
-		sw = nw
+	if v[2] < 0 {
+		acc.Lat = math.NaN()
 	}
 
-	seIndex := min(max(int(se), 0), len(n.values)-1)
-	swIndex := min(max(int(sw), 0), len(n.values)-1)
-	neIndex := min(max(int(ne), 0), len(n.values)-1)
-	nwIndex := min(max(int(nw), 0), len(n.values)-1)
+	return acc
+}
 
-	sse := n.values[seIndex]
-	ssw := n.values[swIndex]
-	sne := n.values[neIndex]
-	snw := n.values[nwIndex]
+// USSurveyFoot is the length of a US survey foot in meters.
+const USSurveyFoot = 1200.0 / 3937
 
-	dx := fcol - col
-	dy := frow - row
+// Unit returns a CRS with the horizontal coordinates of a projected base CRS
+// in another unit. meters is the length of the unit in meters.
+func Unit(base CRS, meters float64) CRS {
+	return unit{
+		base:   base,
+		meters: meters,
+	}
+}
 
-	latsv := (1-dx)*(1-dy)*float64(sse[0]) + dx*(1-dy)*float64(ssw[0]) + (1-dx)*dy*float64(sne[0]) + dx*dy*float64(snw[0])
-	lonsv := (1-dx)*(1-dy)*float64(sse[1]) + dx*(1-dy)*float64(ssw[1]) + (1-dx)*dy*float64(sne[1]) + dx*dy*float64(snw[1])
+type unit struct {
+	base   CRS
+	meters float64
+}
+
+func (u unit) Base() CRS {
+	return u.base
+}
+
+func (u unit) Spheroid() Spheroid {
+	return u.base.Spheroid()
+}
+
+func (u unit) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("Unit(%gm)", u.meters), fromBase)
+}
+
+func (u unit) ToBase(x, y, z float64) (float64, float64, float64) {
+	return x * u.meters, y * u.meters, z
+}
+
+func (u unit) FromBase(x, y, z float64) (float64, float64, float64) {
+	return x / u.meters, y / u.meters, z
+}
+
+func (u unit) ToBaseSlice(xs, ys, _ []float64) error {
+	for i := range xs {
+		xs[i] *= u.meters
+		ys[i] *= u.meters
+	}
+
+	return nil
+}
 
-	return -lonsv / 3600, latsv / 3600
+func (u unit) FromBaseSlice(xs, ys, _ []float64) error {
+	for i := range xs {
+		xs[i] /= u.meters
+		ys[i] /= u.meters
+	}
+
+	return nil
 }
 
 func WebMercator(base CRS) CRS {
@@ -475,9 +1472,25 @@ func (p webMercator) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p webMercator) describe(fromBase bool) string {
+	return inverse("WebMercator", !fromBase)
+}
+
 func (p webMercator) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p webMercator) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.toBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p webMercator) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64) {
 	D := (-north) / s.A
 	phi := math.Pi/2 - 2*math.Atan(math.Pow(math.E, D))
 	lambda := east / s.A
@@ -486,8 +1499,20 @@ func (p webMercator) ToBase(east, north, h float64) (lon, lat, h2 float64) {
 }
 
 func (p webMercator) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p webMercator) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p webMercator) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	lambda := radian(lon)
 	phi := radian(lat)
 
@@ -537,13 +1562,14 @@ func TransverseMercator(base CRS, lonf, latf, scale, eastf, northf float64) CRS
 		M0 = B * xi0
 	}
 
-	h1i := n/2.0 - (2/3.0)*n2 + (37/96.0)*n3 + (1/360.0)*n4
-	h2i := (1/48.0)*n2 - (1/15.0)*n3 + (437/1440.0)*n4
+	h1i := n/2.0 - (2/3.0)*n2 + (37/96.0)*n3 - (1/360.0)*n4
+	h2i := (1/48.0)*n2 + (1/15.0)*n3 - (437/1440.0)*n4
 	h3i := (17/480.0)*n3 - (37/840.0)*n4
 	h4i := (4397 / 161280.0) * n4
 
 	return transverseMercator{
 		base:    base,
+		phiO:    phi0,
 		lambdaO: lambda0,
 		scale:   scale,
 		eastf:   eastf,
@@ -563,12 +1589,13 @@ func TransverseMercator(base CRS, lonf, latf, scale, eastf, northf float64) CRS
 
 type transverseMercator struct {
 	base                  CRS
-	lambdaO               float64
+	phiO, lambdaO         float64
 	b, h1, h2, h3, h4, mO float64
 	h1i, h2i, h3i, h4i    float64
 	scale                 float64
 	eastf                 float64
 	northf                float64
+	tolerance             float64
 }
 
 func (p transverseMercator) Base() CRS {
@@ -579,9 +1606,42 @@ func (p transverseMercator) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p transverseMercator) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("TransverseMercator(lon0=%.10g, lat0=%.10g, k0=%.10g, x0=%.10g, y0=%.10g)", degree(p.lambdaO), degree(p.phiO), p.scale, p.eastf, p.northf), !fromBase)
+}
+
 func (p transverseMercator) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	lon, lat, h2, _ = p.toBase(p.base.Spheroid(), east, north, h)
+
+	return lon, lat, h2
+}
+
+func (p transverseMercator) SafeToBase(east, north, h float64) (lon, lat, h2 float64, err error) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p transverseMercator) SafeFromBase(lon, lat, h float64) (east, north, h2 float64, err error) {
+	east, north, h2 = p.FromBase(lon, lat, h)
+
+	return east, north, h2, nil
+}
+
+func (p transverseMercator) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = p.toBase(s, xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p transverseMercator) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64, err error) {
 	etai := (east - p.eastf) / (p.b * p.scale)
 	xii := ((north - p.northf) + p.scale*p.mO) / (p.b * p.scale)
 
@@ -603,21 +1663,45 @@ func (p transverseMercator) ToBase(east, north, h float64) (lon, lat, h2 float64
 	Qi := math.Asinh(math.Tan(betai))
 	Qii := Qi + (s.E * math.Atanh(s.E*math.Tanh(Qi)))
 
-	for i := 0; i < 15; i++ {
+	// Changes of Q are larger than the changes of the latitude.
+	tol := radian(tolerance(p.tolerance))
+
+	for i := 0; ; i++ {
 		q := Qi + (s.E * math.Atanh(s.E*math.Tanh(Qii)))
+		converged := math.Abs(q-Qii) <= tol || math.IsNaN(q)
 
 		Qii = q
+
+		if converged {
+			break
+		}
+
+		if i == maxIterations {
+			return math.NaN(), math.NaN(), math.NaN(), fmt.Errorf("%w: inverse transverse mercator at %f, %f", ErrNotConverged, east, north)
+		}
 	}
 
 	phi := math.Atan(math.Sinh(Qii))
 	lambda := p.lambdaO + math.Asin(math.Tanh(eta0i)/math.Cos(betai))
 
-	return degree(lambda), degree(phi), h
+	return degree(lambda), degree(phi), h, nil
 }
 
 func (p transverseMercator) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p transverseMercator) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p transverseMercator) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	phi := radian(lat)
 	lambda := radian(lon)
 
@@ -698,9 +1782,25 @@ func (p lambertConformalConic2SP) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p lambertConformalConic2SP) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("LambertConformalConic2SP(lon0=%.10g, lat0=%.10g, lat1=%.10g, lat2=%.10g, x0=%.10g, y0=%.10g)", degree(p.lambdaf), degree(p.phif), degree(p.phi1), degree(p.phi2), p.eastf, p.northf), !fromBase)
+}
+
 func (p lambertConformalConic2SP) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p lambertConformalConic2SP) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.toBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p lambertConformalConic2SP) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64) {
 	ri := math.Sqrt(math.Pow(east-p.eastf, 2) + math.Pow(p.rf-(north-p.northf), 2))
 	if p.n < 0 && ri > 0 {
 		ri = -ri
@@ -727,27 +1827,29 @@ func (p lambertConformalConic2SP) ToBase(east, north, h float64) (lon, lat, h2 f
 }
 
 func (p lambertConformalConic2SP) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p lambertConformalConic2SP) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p lambertConformalConic2SP) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	phi := radian(lat)
 	lambda := radian(lon)
 
 	t := math.Tan(math.Pi/4-phi/2) / math.Pow((1-s.E*math.Sin(phi))/(1+s.E*math.Sin(phi)), s.E/2)
-	tf := math.Tan(math.Pi/4-p.phif/2) / math.Pow((1-s.E*math.Sin(p.phif))/(1+s.E*math.Sin(p.phif)), s.E/2)
-	t1 := math.Tan(math.Pi/4-p.phi1/2) / math.Pow((1-s.E*math.Sin(p.phi1))/(1+s.E*math.Sin(p.phi1)), s.E/2)
-	t2 := math.Tan(math.Pi/4-p.phi2/2) / math.Pow((1-s.E*math.Sin(p.phi2))/(1+s.E*math.Sin(p.phi2)), s.E/2)
-
-	m1 := math.Cos(p.phi1) / math.Sqrt(1-s.E2*sin2(p.phi1))
-	m2 := math.Cos(p.phi2) / math.Sqrt(1-s.E2*sin2(p.phi2))
-
-	n := (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
-	F := m1 / (n * math.Pow(t1, n))
-	r := s.A * F * math.Pow(t, n)
-	rf := s.A * F * math.Pow(tf, n)
-	theta := n * (lambda - p.lambdaf)
+	r := s.A * p.f * math.Pow(t, p.n)
+	theta := p.n * (lambda - p.lambdaf)
 
 	east = p.eastf + r*math.Sin(theta)
-	north = p.northf + rf - r*math.Cos(theta)
+	north = p.northf + p.rf - r*math.Cos(theta)
 
 	return east, north, h
 }
@@ -776,6 +1878,9 @@ func AlbersConicEqualArea(base CRS, lonf, latf, sp1, sp2, eastf, northf float64)
 
 	return albersConicEqualArea{
 		base:    base,
+		phif:    phif,
+		phi1:    phi1,
+		phi2:    phi2,
 		lambdaf: lambdaf,
 		alphaf:  alphaf,
 		n:       n,
@@ -788,6 +1893,7 @@ func AlbersConicEqualArea(base CRS, lonf, latf, sp1, sp2, eastf, northf float64)
 
 type albersConicEqualArea struct {
 	base                      CRS
+	phif, phi1, phi2          float64
 	lambdaf, alphaf, n, c, rf float64
 	eastf                     float64
 	northf                    float64
@@ -801,9 +1907,25 @@ func (p albersConicEqualArea) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p albersConicEqualArea) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("AlbersConicEqualArea(lon0=%.10g, lat0=%.10g, lat1=%.10g, lat2=%.10g, x0=%.10g, y0=%.10g)", degree(p.lambdaf), degree(p.phif), degree(p.phi1), degree(p.phi2), p.eastf, p.northf), !fromBase)
+}
+
 func (p albersConicEqualArea) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p albersConicEqualArea) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.toBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p albersConicEqualArea) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64) {
 	ri := math.Sqrt(math.Pow(east-p.eastf, 2) + math.Pow(p.rf-(north-p.northf), 2))
 	alphai := (p.c - (math.Pow(ri, 2) * math.Pow(p.n, 2) / s.A2)) / p.n
 	betai := math.Asin(alphai / (1 - ((1 - s.E2) / (2 * s.E) * math.Log((1-s.E)/(1+s.E)))))
@@ -823,8 +1945,20 @@ func (p albersConicEqualArea) ToBase(east, north, h float64) (lon, lat, h2 float
 }
 
 func (p albersConicEqualArea) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p albersConicEqualArea) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p albersConicEqualArea) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	lambda := radian(lon)
 	phi := radian(lat)
 
@@ -832,10 +1966,9 @@ func (p albersConicEqualArea) FromBase(lon, lat, h float64) (east, north, h2 flo
 
 	theta := p.n * (lambda - p.lambdaf)
 	r := (s.A * math.Sqrt(p.c-p.n*alpha)) / p.n
-	rf := (s.A * math.Sqrt(p.c-p.n*p.alphaf)) / p.n
 
 	east = p.eastf + r*math.Sin(theta)
-	north = p.northf + rf - r*math.Cos(theta)
+	north = p.northf + p.rf - r*math.Cos(theta)
 
 	return east, north, h
 }
@@ -886,9 +2019,25 @@ func (p lambertAzimuthalEqualArea) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p lambertAzimuthalEqualArea) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("LambertAzimuthalEqualArea(lon0=%.10g, lat0=%.10g, x0=%.10g, y0=%.10g)", degree(p.lambda0), degree(p.phi0), p.eastf, p.northf), !fromBase)
+}
+
 func (p lambertAzimuthalEqualArea) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p lambertAzimuthalEqualArea) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.toBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p lambertAzimuthalEqualArea) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64) {
 	rho := math.Sqrt(math.Pow((east-p.eastf)/p.g, 2) + math.Pow(p.g*(north-p.northf), 2))
 	c := 2 * math.Asin(rho/(2*p.rq))
 	betai := math.Asin((math.Cos(c) * math.Sin(p.beta0)) + ((p.g * (north - p.northf) * math.Sin(c) * math.Cos(p.beta0)) / rho))
@@ -902,22 +2051,30 @@ func (p lambertAzimuthalEqualArea) ToBase(east, north, h float64) (lon, lat, h2
 }
 
 func (p lambertAzimuthalEqualArea) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p lambertAzimuthalEqualArea) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p lambertAzimuthalEqualArea) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	phi := radian(lat)
 	lambda := radian(lon)
 
 	q := (1 - s.E2) * ((math.Sin(phi) / (1 - s.E2*sin2(phi))) - (1 / (2 * s.E) * math.Log((1-s.E*math.Sin(phi))/(1+s.E*math.Sin(phi)))))
 
-	beta0 := math.Asin(p.q0 / p.qp)
-	rq := s.A * math.Sqrt(p.qp/2)
-	g := s.A * (math.Cos(p.phi0) / math.Sqrt(1-s.E2*sin2(p.phi0))) / (rq * math.Cos(beta0))
-
 	beta := math.Asin(q / p.qp)
-	b := rq * math.Sqrt(2/(1+math.Sin(beta0)*math.Sin(beta)+(math.Cos(beta0)*math.Cos(beta)*math.Cos(lambda-p.lambda0))))
+	b := p.rq * math.Sqrt(2/(1+math.Sin(p.beta0)*math.Sin(beta)+(math.Cos(p.beta0)*math.Cos(beta)*math.Cos(lambda-p.lambda0))))
 
-	east = p.eastf + ((b * g) * (math.Cos(beta) * math.Sin(lambda-p.lambda0)))
-	north = p.northf + (b/g)*((math.Cos(beta0)*math.Sin(beta))-(math.Sin(beta0)*math.Cos(beta)*math.Cos(lambda-p.lambda0)))
+	east = p.eastf + ((b * p.g) * (math.Cos(beta) * math.Sin(lambda-p.lambda0)))
+	north = p.northf + (b/p.g)*((math.Cos(p.beta0)*math.Sin(beta))-(math.Sin(p.beta0)*math.Cos(beta)*math.Cos(lambda-p.lambda0)))
 
 	return east, north, h
 }
@@ -943,7 +2100,9 @@ func Krovak(base CRS, lonf, latf, azimuth, sp, scale, eastf, northf float64) CRS
 
 	return krovak{
 		base:    base,
+		phic:    phic,
 		lambda0: lambda0,
+		scale:   scale,
 		phip:    phip,
 		alphac:  alphac,
 		b:       B,
@@ -957,9 +2116,11 @@ func Krovak(base CRS, lonf, latf, azimuth, sp, scale, eastf, northf float64) CRS
 
 type krovak struct {
 	base                                CRS
+	phic, scale                         float64
 	lambda0, phip, alphac, b, t0, n, r0 float64
 	eastf                               float64
 	northf                              float64
+	tolerance                           float64
 }
 
 func (p krovak) Base() CRS {
@@ -970,9 +2131,25 @@ func (p krovak) Spheroid() Spheroid {
 	return p.base.Spheroid()
 }
 
+func (p krovak) describe(fromBase bool) string {
+	return inverse(fmt.Sprintf("Krovak(lon0=%.10g, lat0=%.10g, azimuth=%.10g, lat1=%.10g, k0=%.10g, x0=%.10g, y0=%.10g)", degree(p.lambda0), degree(p.phic), degree(p.alphac), degree(p.phip), p.scale, p.eastf, p.northf), !fromBase)
+}
+
 func (p krovak) FromBase(lon, lat, h float64) (east, north, h2 float64) {
+	return p.fromBase(p.base.Spheroid(), lon, lat, h)
+}
+
+func (p krovak) FromBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	for i := range xs {
+		xs[i], ys[i], zs[i] = p.fromBase(s, xs[i], ys[i], zs[i])
+	}
+
+	return nil
+}
+
+func (p krovak) fromBase(s Spheroid, lon, lat, h float64) (east, north, h2 float64) {
 	phi := radian(lat)
 	lambda := radian(lon)
 
@@ -989,8 +2166,37 @@ func (p krovak) FromBase(lon, lat, h float64) (east, north, h2 float64) {
 }
 
 func (p krovak) ToBase(east, north, h float64) (lon, lat, h2 float64) {
+	lon, lat, h2, _ = p.toBase(p.base.Spheroid(), east, north, h)
+
+	return lon, lat, h2
+}
+
+func (p krovak) SafeToBase(east, north, h float64) (lon, lat, h2 float64, err error) {
+	return p.toBase(p.base.Spheroid(), east, north, h)
+}
+
+func (p krovak) SafeFromBase(lon, lat, h float64) (east, north, h2 float64, err error) {
+	east, north, h2 = p.FromBase(lon, lat, h)
+
+	return east, north, h2, nil
+}
+
+func (p krovak) ToBaseSlice(xs, ys, zs []float64) error {
 	s := p.base.Spheroid()
 
+	var err error
+
+	for i := range xs {
+		xs[i], ys[i], zs[i], err = p.toBase(s, xs[i], ys[i], zs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p krovak) toBase(s Spheroid, east, north, h float64) (lon, lat, h2 float64, err error) {
 	Xpi := (-north) - p.northf
 	Ypi := (-east) - p.eastf
 	ri := math.Sqrt(math.Pow(Xpi, 2) + math.Pow(Ypi, 2))
@@ -1001,14 +2207,34 @@ func (p krovak) ToBase(east, north, h float64) (lon, lat, h2 float64) {
 	vi := math.Asin(math.Cos(ti) * math.Sin(di) / math.Cos(ui))
 
 	phi := ui
+	tol := radian(tolerance(p.tolerance))
+
+	for i := 0; ; i++ {
+		next := 2 * (math.Atan(math.Pow(p.t0, -1/p.b)*math.Pow(math.Tan(ui/2+math.Pi/4), 1/p.b)*math.Pow((1+s.E*math.Sin(phi))/(1-s.E*math.Sin(phi)), s.E/2)) - math.Pi/4)
+		converged := math.Abs(next-phi) <= tol || math.IsNaN(next)
+
+		phi = next
+
+		if converged {
+			break
+		}
 
-	for i := 0; i < 3; i++ {
-		phi = 2 * (math.Atan(math.Pow(p.t0, -1/p.b)*math.Pow(math.Tan(ui/2+math.Pi/4), 1/p.b)*math.Pow((1+s.E*math.Sin(phi))/(1-s.E*math.Sin(phi)), s.E/2)) - math.Pi/4)
+		if i == maxIterations {
+			return math.NaN(), math.NaN(), math.NaN(), fmt.Errorf("%w: inverse krovak at %f, %f", ErrNotConverged, east, north)
+		}
 	}
 
 	lambda := p.lambda0 - vi/p.b
 
-	return degree(lambda), degree(phi), h
+	return degree(lambda), degree(phi), h, nil
+}
+
+func inverse(desc string, inverse bool) string {
+	if inverse {
+		return "inverse " + desc
+	}
+
+	return desc
 }
 
 func sin2(r float64) float64 {
@@ -0,0 +1,384 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// testGrid returns a NTv2 grid of 3x3 nodes between 45N 75W and 45.5N 75.5W
+// with the shifts of f in seconds.
+func testGrid(t *testing.T, f func(lonW, lat float64) [4]float32) CRS {
+	t.Helper()
+
+	records := make([][4]float32, 0, 9)
+
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			records = append(records, f(270000+900*float64(c), 162000+900*float64(r)))
+		}
+	}
+
+	crs, err := NewNTv2(NTv2Header{SystemF: "NAD27", SystemT: "NAD83"}, []NTv2Subgrid{{
+		Name: "TEST", SLat: 162000, NLat: 163800, ELong: 270000, WLong: 271800, LatInc: 900, LongInc: 900,
+		Records: records,
+	}}, NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return crs
+}
+
+func TestOutOfGrid(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(t, func(lonW, lat float64) [4]float32 {
+		return [4]float32{float32(0.3 + lat/3600), float32(-1.5 + lonW/36000), 0.1, 0.1}
+	})
+	fallback := Geographic(HelmertPV(-8, 160, 176, 0, 0, 0, 0), NewSpheroid(6378206.4, 294.9786982))
+
+	tests := []struct {
+		policy OutOfGrid
+		status Status
+		err    error
+		nan    bool
+	}{
+		{OutOfGridError, StatusOK, ErrOutsideDomain, true},
+		{OutOfGridNaN, StatusNaN, nil, true},
+		{OutOfGridExtrapolate, StatusExtrapolated, nil, false},
+		{OutOfGridFallback, StatusFallback, nil, false},
+	}
+
+	extrapolate, err := NewTransformer(WithOutOfGrid(grid, OutOfGridExtrapolate, nil), grid.Base())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		crs := WithOutOfGrid(grid, tt.policy, fallback)
+
+		tr, err := NewTransformer(crs, grid.Base())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, inverse := range []bool{false, true} {
+			f, want := Transform(crs, grid.Base()), tr
+
+			if inverse {
+				tr, f, want = tr.Inverse(), Transform(grid.Base(), crs), tr.Inverse()
+			}
+
+			lon, lat, _, status, err := tr.TransformStatus(-76, 46, 0)
+			if !errors.Is(err, tt.err) || status != tt.status || math.IsNaN(lon+lat) != tt.nan {
+				t.Errorf("policy %d, inverse %t: %f, %f, %s, %v", tt.policy, inverse, lon, lat, status, err)
+			}
+
+			// Func cannot report errors and extrapolates instead.
+			if tt.policy == OutOfGridError {
+				want = extrapolate
+
+				if inverse {
+					want = extrapolate.Inverse()
+				}
+			}
+
+			wlon, wlat, _, _ := want.Transform(-76, 46, 0)
+
+			if lon, lat, _ := f(-76, 46, 0); !sameFloat(lon, wlon) || !sameFloat(lat, wlat) {
+				t.Errorf("policy %d, inverse %t: Func %f, %f, want %f, %f", tt.policy, inverse, lon, lat, wlon, wlat)
+			}
+		}
+	}
+}
+
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestGridNaN(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(t, func(lonW, lat float64) [4]float32 {
+		if lonW == 270000 && lat == 162000 {
+			return [4]float32{float32(math.NaN()), float32(math.NaN()), -1, -1}
+		}
+
+		return [4]float32{0.3, -1.5, -1, -1}
+	})
+
+	tr, err := NewTransformer(grid, grid.Base())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tr := range []Transformer{tr, tr.Inverse()} {
+		lon, lat, _, status, err := tr.TransformStatus(-75.1, 45.1, 0)
+		if err != nil || status != StatusNaN || !math.IsNaN(lon) || !math.IsNaN(lat) {
+			t.Errorf("%s: %f, %f, %s, %v", tr.Pipeline(), lon, lat, status, err)
+		}
+
+		lon, lat, _, status, err = tr.TransformStatus(-75.4, 45.4, 0)
+		if err != nil || status != StatusOK || math.IsNaN(lon+lat) {
+			t.Errorf("%s: %f, %f, %s, %v", tr.Pipeline(), lon, lat, status, err)
+		}
+	}
+}
+
+func TestInverseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	grid := testGrid(t, func(lonW, lat float64) [4]float32 {
+		return [4]float32{float32(0.3 + (lat-162000)/9000), float32(-1.5 + (lonW-270000)/4500), 0.1, 0.1}
+	})
+
+	tests := []struct {
+		name     string
+		from, to CRS
+		lon, lat float64
+	}{
+		{"transverse mercator", EPSG(4258), EPSG(25832), 9, 50},
+		{"transverse mercator far from meridian", EPSG(4258), EPSG(25832), 15, 70},
+		{"transverse mercator south", EPSG(4326), EPSG(32733), 12, -40},
+		{"krovak", EPSG(4156), EPSG(5514), 15, 50},
+		{"krovak edge", EPSG(4156), EPSG(5514), 12.5, 48.5},
+		{"ntv2", grid.Base(), grid, -75.1, 45.1},
+		{"ntv2 node", grid.Base(), grid, -75.25, 45.25},
+	}
+
+	for _, tt := range tests {
+		tr, err := NewTransformer(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x, y, h, err := tr.Transform(tt.lon, tt.lat, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		lon, lat, _, err := tr.Inverse().Transform(x, y, h)
+		if err != nil || math.Abs(lon-tt.lon) > DefaultTolerance || math.Abs(lat-tt.lat) > DefaultTolerance {
+			t.Errorf("%s: %.12f, %.12f, %v, want %.12f, %.12f", tt.name, lon, lat, err, tt.lon, tt.lat)
+		}
+	}
+}
+
+func TestNotConverged(t *testing.T) {
+	t.Parallel()
+
+	// The latitude shift changes twice as fast as the latitude, so the
+	// inverse iteration diverges.
+	grid := testGrid(t, func(_, lat float64) [4]float32 {
+		return [4]float32{float32(2 * (lat - 162900)), 0, 0, 0}
+	})
+
+	tr, err := NewTransformer(grid.Base(), grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := tr.Transform(-75.25, 45.26, 0); !errors.Is(err, ErrNotConverged) {
+		t.Errorf("error %v, want ErrNotConverged", err)
+	}
+
+	if _, _, _, err := tr.Inverse().Transform(-75.25, 45.26, 0); err != nil {
+		t.Errorf("forward: %v", err)
+	}
+}
+
+func TestHelmertConventions(t *testing.T) {
+	t.Parallel()
+
+	// WGS 72 to WGS 84 (EPSG Guidance Note 7-2, 2.4.3.3). The rotation about
+	// the z axis is +0.554" as Position Vector and -0.554" as Coordinate Frame
+	// transformation.
+	tests := []struct {
+		name string
+		crs  CRS
+	}{
+		{"9606", HelmertPV(0, 0, 4.5, 0, 0, 0.554, 0.219)},
+		{"9607", HelmertCF(0, 0, 4.5, 0, 0, -0.554, 0.219)},
+	}
+
+	for _, tt := range tests {
+		x, y, z := tt.crs.ToBase(3657660.66, 255768.55, 5201382.11)
+		if math.Abs(x-3657660.78) > 0.01 || math.Abs(y-255778.43) > 0.01 || math.Abs(z-5201387.75) > 0.01 {
+			t.Errorf("%s: %.2f, %.2f, %.2f, want 3657660.78, 255778.43, 5201387.75", tt.name, x, y, z)
+		}
+
+		x, y, z = tt.crs.FromBase(x, y, z)
+		if math.Abs(x-3657660.66) > 1e-3 || math.Abs(y-255768.55) > 1e-3 || math.Abs(z-5201382.11) > 1e-3 {
+			t.Errorf("%s inverse: %.3f, %.3f, %.3f", tt.name, x, y, z)
+		}
+	}
+}
+
+func TestTimeHelmert(t *testing.T) {
+	t.Parallel()
+
+	// ITRF2014 to ETRF2000 at the reference epoch 2010.0 (EUREF Technical Note
+	// 1, Position Vector convention of the IERS).
+	params := HelmertParams{Tx: 0.0537, Ty: 0.0512, Tz: -0.0551, Rx: 0.000891, Ry: 0.00539, Rz: -0.008712, Ds: 0.00102}
+	rates := HelmertParams{Tx: 0.0001, Ty: 0.0001, Tz: -0.0019, Rx: 0.000081, Ry: 0.00049, Rz: -0.000792, Ds: 0.00011}
+	cf := HelmertParams{Tx: params.Tx, Ty: params.Ty, Tz: params.Tz, Rx: -params.Rx, Ry: -params.Ry, Rz: -params.Rz, Ds: params.Ds}
+	cfRates := HelmertParams{Tx: rates.Tx, Ty: rates.Ty, Tz: rates.Tz, Rx: -rates.Rx, Ry: -rates.Ry, Rz: -rates.Rz, Ds: rates.Ds}
+
+	// The references apply the parameters at the epoch with the formula of the
+	// technical note.
+	tests := []struct {
+		epoch   float64
+		x, y, z float64
+	}{
+		{2010, 4027894.2053, 307045.4601, 4919474.7560},
+		{2020, 4027894.3394, 307045.2875, 4919474.6479},
+		{2024.5, 4027894.3998, 307045.2098, 4919474.5993},
+	}
+
+	for _, crs := range []CRS{TimeHelmert(params, rates, 2010), TimeHelmertCF(cf, cfRates, 2010)} {
+		tr, err := NewTransformer(crs, EPSG(4978))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tt := range tests {
+			x, y, z, err := tr.TransformEpoch(4027894.006, 307045.600, 4919474.910, tt.epoch)
+			if err != nil || math.Abs(x-tt.x) > 1e-4 || math.Abs(y-tt.y) > 1e-4 || math.Abs(z-tt.z) > 1e-4 {
+				t.Errorf("%s at %g: %.4f, %.4f, %.4f, %v", crs, tt.epoch, x, y, z, err)
+			}
+
+			xs, ys, zs := []float64{4027894.006}, []float64{307045.600}, []float64{4919474.910}
+			if err := tr.AtEpoch(tt.epoch).Slice(xs, ys, zs); err != nil || xs[0] != x || ys[0] != y || zs[0] != z {
+				t.Errorf("%s AtEpoch(%g): %.4f, %.4f, %.4f, %v", crs, tt.epoch, xs[0], ys[0], zs[0], err)
+			}
+		}
+
+		// Without an epoch, the reference epoch is used.
+		x, y, z, err := tr.Transform(4027894.006, 307045.600, 4919474.910)
+		if err != nil || math.Abs(x-tests[0].x) > 1e-4 || math.Abs(y-tests[0].y) > 1e-4 || math.Abs(z-tests[0].z) > 1e-4 {
+			t.Errorf("%s without epoch: %.4f, %.4f, %.4f, %v", crs, x, y, z, err)
+		}
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	t.Parallel()
+
+	// Shifts in seconds of 5x5 nodes between 45N 75W and 46N 76W as functions
+	// of the column (westwards) and row.
+	quadratic := func(c, r float64) float64 { return 0.05*c*c + 0.03*r*r + 0.02*c*r }
+	linear := func(c, r float64) float64 { return 0.3 + 0.1*c - 0.2*r }
+
+	grid := func(f func(c, r float64) float64) CRS {
+		records := make([][4]float32, 0, 25)
+
+		for r := 0; r < 5; r++ {
+			for c := 0; c < 5; c++ {
+				records = append(records, [4]float32{float32(f(float64(c), float64(r))), float32(f(float64(r), float64(c))), -1, -1})
+			}
+		}
+
+		crs, err := NewNTv2(NTv2Header{}, []NTv2Subgrid{{
+			Name: "TEST", SLat: 162000, NLat: 165600, ELong: 270000, WLong: 273600, LatInc: 900, LongInc: 900,
+			Records: records,
+		}}, NewSpheroid(6378206.4, 294.9786982), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return crs
+	}
+
+	methods := []Interpolation{InterpolationBilinear, InterpolationBiquadratic, InterpolationBicubic}
+
+	tests := []struct {
+		name    string
+		f       func(c, r float64) float64
+		c, r    float64
+		inexact Interpolation
+	}{
+		// Only the polynomial methods reproduce a quadratic surface.
+		{"quadratic", quadratic, 1.3, 2.6, InterpolationBilinear},
+		{"quadratic", quadratic, 2.5, 1.5, InterpolationBilinear},
+		{"quadratic", quadratic, 1.75, 2.25, InterpolationBilinear},
+		// Nodes beyond the edges are extrapolated linearly.
+		{"edge cell", linear, 0.4, 0.3, -1},
+		{"edge cell", linear, 3.6, 3.8, -1},
+		{"edge", linear, 0, 2.5, -1},
+		{"corner", linear, 4, 4, -1},
+		{"quadratic node", quadratic, 0, 4, -1},
+	}
+
+	for _, tt := range tests {
+		crs := grid(tt.f)
+		lon, lat := -(270000+900*tt.c)/3600, (162000+900*tt.r)/3600
+
+		for _, method := range methods {
+			lon2, lat2, _ := WithInterpolation(crs, method).ToBase(lon, lat, 0)
+			slat, slonW := (lat2-lat)*3600, (lon-lon2)*3600
+
+			exact := math.Abs(slat-tt.f(tt.c, tt.r)) < 1e-6 && math.Abs(slonW-tt.f(tt.r, tt.c)) < 1e-6
+			if exact == (method == tt.inexact) {
+				t.Errorf("%s %s at %g, %g: %f\", %f\", want %f\", %f\"", tt.name, method, tt.c, tt.r,
+					slat, slonW, tt.f(tt.c, tt.r), tt.f(tt.r, tt.c))
+			}
+		}
+	}
+}
+
+func TestNTv2Subgrids(t *testing.T) {
+	t.Parallel()
+
+	subgrid := func(name, parent string, south, east, size, inc, shift float64) NTv2Subgrid {
+		n := int(size/inc) + 1
+		records := make([][4]float32, n*n)
+
+		for i := range records {
+			records[i] = [4]float32{float32(shift), 0, -1, -1}
+		}
+
+		return NTv2Subgrid{
+			Name: name, Parent: parent, SLat: south * 3600, NLat: (south + size) * 3600, ELong: east * 3600, WLong: (east + size) * 3600,
+			LatInc: inc * 3600, LongInc: inc * 3600, Records: records,
+		}
+	}
+
+	// The latitude shift in seconds identifies the subgrid. B and C overlap.
+	crs, err := NewNTv2(NTv2Header{}, []NTv2Subgrid{
+		subgrid("PARENT", "", 45, 75, 1, 0.25, 1),
+		subgrid("A", "PARENT", 45.25, 75.25, 0.25, 0.125, 2),
+		subgrid("B", "PARENT", 45.5, 75.5, 0.25, 0.125, 3),
+		subgrid("C", "PARENT", 45.625, 75.625, 0.25, 0.125, 5),
+		subgrid("GRANDCHILD", "A", 45.25, 75.25, 0.125, 0.0625, 4),
+	}, NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lon, lat float64
+		shift    float64
+	}{
+		{"parent", -75.1, 45.1, 1},
+		{"child", -75.45, 45.45, 2},
+		{"grandchild", -75.3, 45.3, 4},
+		{"grandchild corner", -75.25, 45.25, 4},
+		{"grandchild edge", -75.375, 45.3, 4},
+		{"child edge", -75.4, 45.5, 2},
+		{"first of touching children", -75.5, 45.5, 2},
+		{"first of overlapping children", -75.7, 45.7, 3},
+		{"second of overlapping children", -75.8, 45.8, 5},
+		{"parent corner", -76, 46, 1},
+		{"parent edge", -75, 45.6, 1},
+	}
+
+	for _, tt := range tests {
+		_, lat, _ := crs.ToBase(tt.lon, tt.lat, 0)
+		if shift := (lat - tt.lat) * 3600; math.Abs(shift-tt.shift) > 1e-6 {
+			t.Errorf("%s: shift %f\", want %f\"", tt.name, shift, tt.shift)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+//nolint:varnamelen,gomnd
+package wgs84
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// NTv2Header is the overview header of a NTv2 file.
+type NTv2Header struct {
+	Version          string
+	SystemF, SystemT string
+	MajorF, MinorF   float64
+	MajorT, MinorT   float64
+}
+
+// NTv2Subgrid is a subgrid of a NTv2 file in seconds, longitudes positive west.
+// Records start in the south-east corner and go row by row from east to west.
+type NTv2Subgrid struct {
+	Name, Parent     string
+	Created, Updated string
+	SLat, NLat       float64
+	ELong, WLong     float64
+	LatInc, LongInc  float64
+	Records          [][4]float32
+}
+
+// NewNTv2 returns a grid shift of the subgrids, which can be written with
+// WriteNTv2. Parents have to precede their children. The grid shifts
+// coordinates on the spheroid to the base CRS, which defaults to EPSG 4326.
+func NewNTv2(header NTv2Header, subgrids []NTv2Subgrid, spheroid Spheroid, base CRS) (CRS, error) {
+	if base == nil {
+		base = Geographic(nil, NewSpheroid(6378137, 298.257223563))
+	}
+
+	if len(subgrids) == 0 {
+		return nil, errors.New("invalid ntv2: no subgrids")
+	}
+
+	data := ntv2{
+		spheroid: spheroid,
+		base:     base,
+		numOrec:  11,
+		numSrec:  11,
+		numFile:  int32(len(subgrids)),
+		gsType:   "SECONDS",
+		version:  header.Version,
+		systemF:  header.SystemF,
+		systemT:  header.SystemT,
+		majorF:   header.MajorF,
+		minorF:   header.MinorF,
+		majorT:   header.MajorT,
+		minorT:   header.MinorT,
+	}
+
+	if data.version == "" {
+		data.version = "NTv2.0"
+	}
+
+	named := make(map[string]*ntv2Grid, len(subgrids))
+
+	for _, s := range subgrids {
+		grid := &ntv2Grid{
+			name:    s.Name,
+			parent:  s.Parent,
+			created: s.Created,
+			updated: s.Updated,
+			sLat:    s.SLat,
+			nLat:    s.NLat,
+			eLong:   s.ELong,
+			wLong:   s.WLong,
+			latInc:  s.LatInc,
+			longInc: s.LongInc,
+			gsCount: int32(len(s.Records)),
+			records: memRecords(append([][4]float32(nil), s.Records...)),
+		}
+
+		if grid.latInc <= 0 || grid.longInc <= 0 || grid.rows()*grid.cols() != len(s.Records) {
+			return nil, fmt.Errorf("invalid ntv2 subgrid %q: %d records, LAT_INC %f, LONG_INC %f", s.Name, len(s.Records), s.LatInc, s.LongInc)
+		}
+
+		if _, ok := named[s.Name]; ok || s.Name == "" {
+			return nil, fmt.Errorf("invalid ntv2 subgrid %q: duplicate name", s.Name)
+		}
+
+		if grid.parent == "" || grid.parent == "NONE" {
+			grid.parent = "NONE"
+			data.grids = append(data.grids, grid)
+		} else {
+			parent, ok := named[grid.parent]
+			if !ok {
+				return nil, fmt.Errorf("invalid ntv2 subgrid %q: parent %q not found", s.Name, s.Parent)
+			}
+
+			parent.children = append(parent.children, grid)
+		}
+
+		named[s.Name] = grid
+	}
+
+	data.name = subgrids[0].Name
+
+	return data, nil
+}
+
+// WriteNTv2File writes a grid shift to a NTv2 file. See WriteNTv2.
+func WriteNTv2File(path string, crs CRS) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteNTv2(file, crs); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// WriteNTv2 writes a grid shift as little endian NTv2 file. Grids of other
+// formats are written in seconds.
+func WriteNTv2(w io.Writer, crs CRS) error {
+	data, err := gridShift(crs)
+	if err != nil {
+		return err
+	}
+
+	if len(data.grids) == 0 {
+		return errors.New("invalid ntv2: no subgrids")
+	}
+
+	version := data.version
+	if version == "" {
+		version = "NTv2.0"
+	}
+
+	var grids []*ntv2Grid
+
+	var walk func([]*ntv2Grid)
+
+	walk = func(gs []*ntv2Grid) {
+		for _, g := range gs {
+			grids = append(grids, g)
+			walk(g.children)
+		}
+	}
+
+	walk(data.grids)
+
+	gsType, unit := "SECONDS", 1.0
+
+	switch data.gsType {
+	case "MINUTES":
+		gsType, unit = data.gsType, 60
+	case "DEGREES":
+		gsType, unit = data.gsType, 3600
+	}
+
+	nw := ntv2Writer{w: bufio.NewWriter(w)}
+
+	nw.int("NUM_OREC", 11)
+	nw.int("NUM_SREC", 11)
+	nw.int("NUM_FILE", int32(len(grids)))
+	nw.string("GS_TYPE", gsType)
+	nw.string("VERSION", version)
+	nw.string("SYSTEM_F", data.systemF)
+	nw.string("SYSTEM_T", data.systemT)
+	nw.float("MAJOR_F", data.majorF)
+	nw.float("MINOR_F", data.minorF)
+	nw.float("MAJOR_T", data.majorT)
+	nw.float("MINOR_T", data.minorT)
+
+	for i, g := range grids {
+		name, parent := g.name, g.parent
+
+		if name == "" {
+			name = fmt.Sprint(i)
+		}
+
+		if parent == "" {
+			parent = "NONE"
+		}
+
+		nw.string("SUB_NAME", name)
+		nw.string("PARENT", parent)
+		nw.string("CREATED", g.created)
+		nw.string("UPDATED", g.updated)
+		header := g.header
+		if g.unit != unit {
+			header = [6]float64{g.sLat / unit, g.nLat / unit, g.eLong / unit, g.wLong / unit, g.latInc / unit, g.longInc / unit}
+		}
+
+		nw.float("S_LAT", header[0])
+		nw.float("N_LAT", header[1])
+		nw.float("E_LONG", header[2])
+		nw.float("W_LONG", header[3])
+		nw.float("LAT_INC", header[4])
+		nw.float("LONG_INC", header[5])
+		nw.int("GS_COUNT", int32(g.records.len()))
+
+		scale := float32(g.seconds() / unit)
+
+		for j := 0; j < g.records.len(); j++ {
+			rec := g.records.at(j)
+
+			if scale != 1 {
+				for k := range rec {
+					rec[k] *= scale
+				}
+			}
+
+			nw.record(rec)
+		}
+
+		if err := g.records.err(); err != nil {
+			return err
+		}
+	}
+
+	if len(data.trailer) >= 8 {
+		copy(nw.buf[8:], data.trailer[:8])
+		nw.write("END")
+		nw.bytes(data.trailer[8:])
+	} else {
+		nw.string("END", "")
+	}
+
+	if nw.err != nil {
+		return nw.err
+	}
+
+	return nw.w.Flush()
+}
+
+// gridShift returns the grid shift of a CRS.
+func gridShift(crs CRS) (ntv2, error) {
+	switch c := crs.(type) {
+	case ntv2:
+		return c, nil
+	case bounded:
+		return gridShift(c.crs)
+	case lazyGrid:
+		return gridShift(c.load())
+	case errorCRS:
+		return ntv2{}, c.err
+	default:
+		return ntv2{}, fmt.Errorf("%T is no grid shift", crs)
+	}
+}
+
+type ntv2Writer struct {
+	w   *bufio.Writer
+	buf [16]byte
+	err error
+}
+
+func (nw *ntv2Writer) write(key string) {
+	if nw.err != nil {
+		return
+	}
+
+	copy(nw.buf[:8], fmt.Sprintf("%-8s", key))
+
+	_, nw.err = nw.w.Write(nw.buf[:])
+}
+
+func (nw *ntv2Writer) int(key string, v int32) {
+	nw.buf = [16]byte{}
+	binary.LittleEndian.PutUint32(nw.buf[8:], uint32(v))
+	nw.write(key)
+}
+
+func (nw *ntv2Writer) string(key, v string) {
+	if len(v) > 8 {
+		v = v[:8]
+	}
+
+	copy(nw.buf[8:], fmt.Sprintf("%-8s", v))
+	nw.write(key)
+}
+
+func (nw *ntv2Writer) float(key string, v float64) {
+	binary.LittleEndian.PutUint64(nw.buf[8:], math.Float64bits(v))
+	nw.write(key)
+}
+
+func (nw *ntv2Writer) record(r [4]float32) {
+	if nw.err != nil {
+		return
+	}
+
+	var b [16]byte
+
+	for i, v := range r {
+		binary.LittleEndian.PutUint32(b[i*4:], math.Float32bits(v))
+	}
+
+	_, nw.err = nw.w.Write(b[:])
+}
+
+func (nw *ntv2Writer) bytes(b []byte) {
+	if nw.err != nil {
+		return
+	}
+
+	_, nw.err = nw.w.Write(b)
+}
@@ -0,0 +1,89 @@
+//nolint:varnamelen
+package wgs84
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestWriteNTv2RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// BeTA2007.gsb has a single subgrid in seconds, testdata/minutes.gsb has
+	// a subgrid with a child in minutes.
+	tests := []string{"ntv2/BeTA2007.gsb", "testdata/minutes.gsb"}
+
+	for _, path := range tests {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		crs, err := NTv2File(path, NewSpheroid(6377397.155, 299.1528128), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			var buf bytes.Buffer
+
+			if err := WriteNTv2(&buf, crs); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(buf.Bytes(), raw) {
+				t.Fatalf("%s: written %d bytes differ from %d bytes read", path, buf.Len(), len(raw))
+			}
+
+			if crs, err = NTv2(bytes.NewReader(buf.Bytes()), NewSpheroid(6377397.155, 299.1528128), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestNTv2Minutes(t *testing.T) {
+	t.Parallel()
+
+	crs, err := NTv2File("testdata/minutes.gsb", NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := WriteNTv2(&buf, testGrid(t, func(_, _ float64) [4]float32 { return [4]float32{0.3, -1.5, 0.1, 0.1} })); err != nil {
+		t.Fatal(err)
+	}
+
+	seconds, err := NTv2(bytes.NewReader(buf.Bytes()), NewSpheroid(6378206.4, 294.9786982), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		crs        CRS
+		lon, lat   float64
+		slon, slat float64
+	}{
+		{crs, -75, 45, 1.5, 0.3},
+		{crs, -75.1, 45.1, 1.452, 0.324},
+		{crs, -75.4, 45.4, 1.308, 0.396},
+		{seconds, -75.4, 45.4, 1.5, 0.3},
+	}
+
+	for _, tt := range tests {
+		lon, lat, _ := tt.crs.ToBase(tt.lon, tt.lat, 0)
+
+		if math.Abs((lon-tt.lon)*3600-tt.slon) > 1e-5 || math.Abs((lat-tt.lat)*3600-tt.slat) > 1e-5 {
+			t.Errorf("%f, %f: shift %f\", %f\", want %f\", %f\"", tt.lon, tt.lat,
+				(lon-tt.lon)*3600, (lat-tt.lat)*3600, tt.slon, tt.slat)
+		}
+	}
+
+	if gsType := seconds.(ntv2).gsType; gsType != "SECONDS" {
+		t.Errorf("GS_TYPE %s, want SECONDS", gsType)
+	}
+}